@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+// maxPeticionPalabra es el tamaño máximo en bytes del cuerpo al agregar o
+// cambiar una palabra
+const maxPeticionPalabra = 4 * 1024
+
+// RegistrarAdmin añade al mux las rutas de administración del diccionario.
+// Las categorías son las listas de words.json con su ruta en el JSON
+// ("complementos.lugares"). Quien lo llama protege el mux con un token.
+func (h *OracionHandler) RegistrarAdmin(mux *http.ServeMux) {
+	mux.HandleFunc("/api/admin/dictionary/reload", h.HandleAdminRecargarDiccionario)
+	mux.HandleFunc("GET /api/admin/dictionary/categories", h.handleAdminCategorias)
+	mux.HandleFunc("GET /api/admin/dictionary/categories/{categoria}", h.handleAdminCategoria)
+	mux.HandleFunc("POST /api/admin/dictionary/categories/{categoria}", h.handleAdminAgregarPalabra)
+	mux.HandleFunc("PUT /api/admin/dictionary/categories/{categoria}/{palabra}", h.handleAdminCambiarPalabra)
+	mux.HandleFunc("DELETE /api/admin/dictionary/categories/{categoria}/{palabra}", h.handleAdminEliminarPalabra)
+	mux.HandleFunc("GET /api/admin/dictionary/words", h.handleAdminBuscarPalabras)
+}
+
+// HandleAdminRecargarDiccionario vuelve a leer words.json. Si el archivo no es
+// válido responde con el error y el diccionario anterior sigue en uso.
+func (h *OracionHandler) HandleAdminRecargarDiccionario(w http.ResponseWriter, r *http.Request) {
+	soloMetodo(http.MethodPost, h.recargarDiccionario)(w, r)
+}
+
+// recargarDiccionario atiende HandleAdminRecargarDiccionario una vez
+// comprobado el método
+func (h *OracionHandler) recargarDiccionario(w http.ResponseWriter, _ *http.Request) {
+	if err := parser.RecargarDiccionario(); err != nil {
+		h.logger.Println("Dictionary reload failed, keeping the previous one:", err)
+		escribirErrorAPI(w, http.StatusUnprocessableEntity, ErrorDiccionarioInvalido, err.Error())
+		return
+	}
+
+	escribirJSON(w, http.StatusOK, map[string]any{"estado": "reloaded", "entradas": parser.EntradasDiccionario()})
+}
+
+// handleAdminCategorias lista las categorías con sus palabras
+func (h *OracionHandler) handleAdminCategorias(w http.ResponseWriter, _ *http.Request) {
+	escribirJSON(w, http.StatusOK, parser.CategoriasDiccionario())
+}
+
+// handleAdminCategoria lista las palabras de una categoría
+func (h *OracionHandler) handleAdminCategoria(w http.ResponseWriter, r *http.Request) {
+	categoria := r.PathValue("categoria")
+	palabras, err := parser.PalabrasDeCategoria(categoria)
+	if err != nil {
+		h.escribirErrorEdicion(w, err)
+		return
+	}
+	escribirJSON(w, http.StatusOK, models.CategoriaDiccionario{Categoria: categoria, Palabras: palabras})
+}
+
+// handleAdminBuscarPalabras busca en todas las categorías las palabras que
+// contienen el parámetro q
+func (h *OracionHandler) handleAdminBuscarPalabras(w http.ResponseWriter, r *http.Request) {
+	consulta := strings.TrimSpace(r.URL.Query().Get("q"))
+	if consulta == "" {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPeticionInvalida, "q must not be empty")
+		return
+	}
+	escribirJSON(w, http.StatusOK, parser.BuscarEnCategorias(consulta))
+}
+
+// handleAdminAgregarPalabra agrega una palabra a una categoría
+func (h *OracionHandler) handleAdminAgregarPalabra(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionPalabra
+	r.Body = http.MaxBytesReader(w, r.Body, maxPeticionPalabra)
+	if !leerPeticion(w, r, &peticion) {
+		return
+	}
+	if strings.TrimSpace(peticion.Palabra) == "" {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPeticionInvalida, "palabra must not be empty")
+		return
+	}
+
+	categoria := r.PathValue("categoria")
+	if err := parser.AgregarPalabra(categoria, peticion.Palabra); err != nil {
+		h.escribirErrorEdicion(w, err)
+		return
+	}
+	h.logger.Printf("Dictionary: added %q to %s", peticion.Palabra, categoria)
+	escribirJSON(w, http.StatusCreated, models.EntradaCategoria{
+		Palabra:   parser.LimpiarPalabra(peticion.Palabra),
+		Categoria: categoria,
+	})
+}
+
+// handleAdminCambiarPalabra corrige una palabra o la mueve de categoría; los
+// campos vacíos del cuerpo no cambian
+func (h *OracionHandler) handleAdminCambiarPalabra(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionPalabra
+	r.Body = http.MaxBytesReader(w, r.Body, maxPeticionPalabra)
+	if !leerPeticion(w, r, &peticion) {
+		return
+	}
+	if strings.TrimSpace(peticion.Palabra) == "" && peticion.Categoria == "" {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPeticionInvalida, "palabra or categoria must be set")
+		return
+	}
+
+	categoria, palabra := r.PathValue("categoria"), r.PathValue("palabra")
+	nueva := models.EntradaCategoria{Palabra: peticion.Palabra, Categoria: peticion.Categoria}
+	if strings.TrimSpace(nueva.Palabra) == "" {
+		nueva.Palabra = palabra
+	}
+	if nueva.Categoria == "" {
+		nueva.Categoria = categoria
+	}
+	nueva.Palabra = parser.LimpiarPalabra(nueva.Palabra)
+
+	if err := parser.CambiarPalabra(categoria, palabra, nueva.Categoria, nueva.Palabra); err != nil {
+		h.escribirErrorEdicion(w, err)
+		return
+	}
+	h.logger.Printf("Dictionary: changed %q in %s to %q in %s", palabra, categoria, nueva.Palabra, nueva.Categoria)
+	escribirJSON(w, http.StatusOK, nueva)
+}
+
+// handleAdminEliminarPalabra quita una palabra de una categoría
+func (h *OracionHandler) handleAdminEliminarPalabra(w http.ResponseWriter, r *http.Request) {
+	categoria, palabra := r.PathValue("categoria"), r.PathValue("palabra")
+	if err := parser.EliminarPalabra(categoria, palabra); err != nil {
+		h.escribirErrorEdicion(w, err)
+		return
+	}
+	h.logger.Printf("Dictionary: removed %q from %s", palabra, categoria)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// escribirErrorEdicion responde con el estado y el código de la API que
+// corresponden a un error al consultar o editar el diccionario
+func (h *OracionHandler) escribirErrorEdicion(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, parser.ErrCategoriaDesconocida), errors.Is(err, parser.ErrPalabraNoEncontrada):
+		escribirErrorAPI(w, http.StatusNotFound, ErrorNoEncontrado, err.Error())
+	case errors.Is(err, parser.ErrPalabraExistente):
+		escribirErrorAPI(w, http.StatusConflict, ErrorPalabraExistente, err.Error())
+	case errors.Is(err, parser.ErrSoloLectura):
+		escribirErrorAPI(w, http.StatusConflict, ErrorSoloLectura, err.Error())
+	case errors.Is(err, parser.ErrDiccionarioInvalido):
+		escribirErrorAPI(w, http.StatusUnprocessableEntity, ErrorDiccionarioInvalido, err.Error())
+	default:
+		h.logger.Println("Error saving the dictionary:", err)
+		escribirErrorAPI(w, http.StatusInternalServerError, ErrorInterno, "the dictionary could not be saved")
+	}
+}
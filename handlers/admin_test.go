@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"validar_oraciones/middleware"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+func TestHandleAdminRecargarDiccionario(t *testing.T) {
+	h := handlerDePrueba(models.NewValidadorConfig())
+	handler := middleware.RequireToken(http.HandlerFunc(h.HandleAdminRecargarDiccionario), "secret")
+
+	tests := []struct {
+		name         string
+		metodo       string
+		autorizacion string
+		estado       int
+	}{
+		{"reload", http.MethodPost, "Bearer secret", http.StatusOK},
+		{"wrong token", http.MethodPost, "Bearer guess", http.StatusUnauthorized},
+		{"no token", http.MethodPost, "", http.StatusUnauthorized},
+		{"wrong method", http.MethodGet, "Bearer secret", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.metodo, "/api/admin/dictionary/reload", nil)
+			if tt.autorizacion != "" {
+				req.Header.Set("Authorization", tt.autorizacion)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.estado {
+				t.Fatalf("status = %d, expected %d: %s", rec.Code, tt.estado, rec.Body.String())
+			}
+			if tt.estado != http.StatusOK {
+				return
+			}
+
+			var respuesta struct {
+				Estado   string `json:"estado"`
+				Entradas int    `json:"entradas"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&respuesta); err != nil || respuesta.Entradas == 0 {
+				t.Errorf("response = %+v (%v), expected the number of entries", respuesta, err)
+			}
+		})
+	}
+}
+
+func TestAdminDiccionario(t *testing.T) {
+	// Las ediciones se guardan en una copia de words.json
+	contenido, err := os.ReadFile("words.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruta := filepath.Join(t.TempDir(), "words.json")
+	if err := os.WriteFile(ruta, contenido, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.CargarDiccionario(ruta); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := parser.CargarDiccionarioFS(os.DirFS("."), "words.json"); err != nil {
+			t.Errorf("restoring the dictionary: %v", err)
+		}
+	})
+
+	h := handlerDePrueba(models.NewValidadorConfig())
+	admin := http.NewServeMux()
+	h.RegistrarAdmin(admin)
+	handler := middleware.RequireToken(admin, "secret")
+
+	pedirAdmin := func(metodo, ruta, cuerpo string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(metodo, ruta, strings.NewReader(cuerpo))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	pasos := []struct {
+		name   string
+		metodo string
+		ruta   string
+		cuerpo string
+		estado int
+		codigo string // Código de error esperado
+	}{
+		{"list categories", http.MethodGet, "/api/admin/dictionary/categories", "", http.StatusOK, ""},
+		{"list a category", http.MethodGet, "/api/admin/dictionary/categories/complementos.lugares", "", http.StatusOK, ""},
+		{"unknown category", http.MethodGet, "/api/admin/dictionary/categories/verbos.formas", "", http.StatusNotFound, ErrorNoEncontrado},
+		{"add", http.MethodPost, "/api/admin/dictionary/categories/complementos.lugares", `{"palabra":"Barranquilla"}`, http.StatusCreated, ""},
+		{"add twice", http.MethodPost, "/api/admin/dictionary/categories/complementos.lugares", `{"palabra":"barranquilla"}`, http.StatusConflict, ErrorPalabraExistente},
+		{"add an empty word", http.MethodPost, "/api/admin/dictionary/categories/complementos.lugares", `{"palabra":" "}`, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"search", http.MethodGet, "/api/admin/dictionary/words?q=RANQUI", "", http.StatusOK, ""},
+		{"search without a query", http.MethodGet, "/api/admin/dictionary/words", "", http.StatusBadRequest, ErrorPeticionInvalida},
+		{"move", http.MethodPut, "/api/admin/dictionary/categories/complementos.lugares/barranquilla", `{"categoria":"sujeto"}`, http.StatusOK, ""},
+		{"update nothing", http.MethodPut, "/api/admin/dictionary/categories/sujeto/barranquilla", `{}`, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"delete", http.MethodDelete, "/api/admin/dictionary/categories/sujeto/barranquilla", "", http.StatusNoContent, ""},
+		{"delete a missing word", http.MethodDelete, "/api/admin/dictionary/categories/sujeto/barranquilla", "", http.StatusNotFound, ErrorNoEncontrado},
+		{"reload with the wrong method", http.MethodGet, "/api/admin/dictionary/reload", "", http.StatusMethodNotAllowed, ErrorMetodo},
+	}
+	for _, tt := range pasos {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := pedirAdmin(tt.metodo, tt.ruta, tt.cuerpo)
+			if rec.Code != tt.estado {
+				t.Fatalf("status = %d, expected %d: %s", rec.Code, tt.estado, rec.Body.String())
+			}
+			if tt.codigo != "" {
+				var respuesta models.RespuestaError
+				if err := json.NewDecoder(rec.Body).Decode(&respuesta); err != nil || respuesta.Error.Codigo != tt.codigo {
+					t.Errorf("error = %+v (%v), expected %s", respuesta, err, tt.codigo)
+				}
+			}
+
+			// Cada cambio se aplica al diccionario en uso
+			palabra, existe := parser.BuscarPalabra("barranquilla")
+			switch tt.name {
+			case "add":
+				if !existe || palabra.Tipo != models.TipoComplemento {
+					t.Errorf("barranquilla = %+v, %v, expected a complement", palabra, existe)
+				}
+			case "search":
+				var resultados []models.EntradaCategoria
+				json.NewDecoder(rec.Body).Decode(&resultados)
+				if len(resultados) != 1 || resultados[0].Categoria != "complementos.lugares" {
+					t.Errorf("results = %+v, expected barranquilla in complementos.lugares", resultados)
+				}
+			case "move":
+				if !existe || palabra.Tipo != models.TipoSujeto {
+					t.Errorf("barranquilla = %+v, %v, expected a subject", palabra, existe)
+				}
+			case "delete":
+				if existe {
+					t.Error("the deleted word is still in the dictionary")
+				}
+			}
+		})
+	}
+
+	t.Run("reload an invalid file", func(t *testing.T) {
+		if err := os.WriteFile(ruta, []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		rec := pedirAdmin(http.MethodPost, "/api/admin/dictionary/reload", "")
+		var respuesta models.RespuestaError
+		if err := json.NewDecoder(rec.Body).Decode(&respuesta); err != nil || rec.Code != http.StatusUnprocessableEntity ||
+			respuesta.Error.Codigo != ErrorDiccionarioInvalido {
+			t.Errorf("reload = %d %+v (%v), expected 422 %s", rec.Code, respuesta, err, ErrorDiccionarioInvalido)
+		}
+		if _, existe := parser.BuscarPalabra("went"); !existe {
+			t.Error("the previous dictionary is no longer in use")
+		}
+	})
+
+	t.Run("requires the token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/dictionary/categories", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, expected 401", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+// especificacionAPI es el documento OpenAPI 3 de /api/v1; las pruebas comprueban
+// que coincide con los handlers
+//
+//go:embed openapi.json
+var especificacionAPI []byte
+
+// Códigos de error de la API
+const (
+	ErrorPeticionInvalida = "invalid_request"
+	ErrorPerfilInvalido   = "invalid_profile"
+	ErrorTextoVacio       = "empty_text"
+	ErrorLoteInvalido     = "invalid_batch"
+	ErrorNoEncontrado     = "not_found"
+	ErrorMetodo           = "method_not_allowed"
+	ErrorInterno          = "internal_error"
+	ErrorTiempoAgotado    = "timeout"
+	ErrorCancelada        = "cancelled"
+
+	// Administración del diccionario
+	ErrorPalabraExistente    = "word_exists"
+	ErrorDiccionarioInvalido = "invalid_dictionary"
+	ErrorSoloLectura         = "read_only_dictionary"
+)
+
+// rutaAPI asocia una ruta de /api/v1 con el método que acepta y su handler
+type rutaAPI struct {
+	metodo  string
+	patron  string
+	handler http.HandlerFunc
+}
+
+// rutasAPIV1 enumera las rutas de la API versionada
+func (h *OracionHandler) rutasAPIV1() []rutaAPI {
+	return []rutaAPI{
+		{http.MethodPost, "/api/v1/validate", h.handleV1Validar},
+		{http.MethodPost, "/api/v1/validate/batch", h.handleV1Lote},
+		{http.MethodPost, "/api/v1/tokenize", h.handleV1Tokenizar},
+		{http.MethodGet, "/api/v1/dictionary/{word}", h.handleV1Diccionario},
+		{http.MethodGet, "/api/v1/openapi.json", handleV1Especificacion},
+	}
+}
+
+// RegistrarAPIV1 añade las rutas de /api/v1 al mux. Las rutas desconocidas y
+// los métodos no admitidos también responden con un error JSON.
+func (h *OracionHandler) RegistrarAPIV1(mux *http.ServeMux) {
+	for _, ruta := range h.rutasAPIV1() {
+		mux.HandleFunc(ruta.patron, soloMetodo(ruta.metodo, ruta.handler))
+	}
+	mux.HandleFunc("/api/v1/", func(w http.ResponseWriter, r *http.Request) {
+		escribirErrorAPI(w, http.StatusNotFound, ErrorNoEncontrado, fmt.Sprintf("no API route for %s", r.URL.Path))
+	})
+}
+
+// soloMetodo rechaza las peticiones con otro método
+func soloMetodo(metodo string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != metodo {
+			w.Header().Set("Allow", metodo)
+			escribirErrorAPI(w, http.StatusMethodNotAllowed, ErrorMetodo, fmt.Sprintf("method %s not allowed, use %s", r.Method, metodo))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// handleV1Validar valida un texto con una o varias oraciones
+func (h *OracionHandler) handleV1Validar(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionValidacion
+	if !leerPeticion(w, r, &peticion) {
+		return
+	}
+
+	opciones, perfil, err := h.opcionesAPI(peticion.Perfil, peticion.PuntuacionEstricta)
+	if err != nil {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPerfilInvalido, err.Error())
+		return
+	}
+
+	respuesta, apiErr := h.validarTextoAPI(parser.ValidadorActual(), peticion.Texto, perfil, opciones)
+	if apiErr != nil {
+		escribirErrorAPI(w, estadoError(apiErr), apiErr.Codigo, apiErr.Mensaje)
+		return
+	}
+	escribirJSON(w, http.StatusOK, respuesta)
+}
+
+// handleV1Lote valida varios textos; un texto que no se puede validar no impide
+// validar los demás
+func (h *OracionHandler) handleV1Lote(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionLote
+	if !leerPeticion(w, r, &peticion) {
+		return
+	}
+
+	if len(peticion.Textos) == 0 {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorLoteInvalido, "texts must contain at least one text")
+		return
+	}
+	if len(peticion.Textos) > h.config.MaxLote {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorLoteInvalido, fmt.Sprintf("a batch accepts at most %d texts", h.config.MaxLote))
+		return
+	}
+
+	opciones, perfil, err := h.opcionesAPI(peticion.Perfil, peticion.PuntuacionEstricta)
+	if err != nil {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPerfilInvalido, err.Error())
+		return
+	}
+
+	validador := parser.ValidadorActual()
+	respuesta := models.RespuestaLote{Resultados: make([]models.ResultadoLote, len(peticion.Textos))}
+	err = procesarEnParalelo(r.Context(), len(peticion.Textos), h.config.Trabajadores, func(i int) {
+		resultado, apiErr := h.validarTextoAPI(validador, peticion.Textos[i], perfil, opciones)
+		respuesta.Resultados[i] = models.ResultadoLote{Indice: i, Resultado: resultado, Error: apiErr}
+	})
+	if err != nil {
+		if estado, codigo, mensaje, responder := h.cancelacion(r, err); responder {
+			escribirErrorAPI(w, estado, codigo, mensaje)
+		}
+		return
+	}
+	escribirJSON(w, http.StatusOK, respuesta)
+}
+
+// handleV1Tokenizar devuelve el análisis léxico de un texto sin validarlo
+func (h *OracionHandler) handleV1Tokenizar(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionTokenizar
+	if !leerPeticion(w, r, &peticion) {
+		return
+	}
+	if strings.TrimSpace(peticion.Texto) == "" {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorTextoVacio, "text must not be empty")
+		return
+	}
+
+	segmentos, tokens, err := h.tokenizarTexto(parser.ValidadorActual(), peticion.Texto)
+	if err != nil {
+		h.logger.Printf("Error in lexical analysis: %v", err)
+		escribirErrorAPI(w, http.StatusInternalServerError, ErrorInterno, "error in sentence analysis")
+		return
+	}
+
+	escribirJSON(w, http.StatusOK, models.RespuestaTokenizar{
+		Texto:     peticion.Texto,
+		Oraciones: oracionesAPI(segmentos),
+		Tokens:    tokensAPI(tokens),
+	})
+}
+
+// handleV1Diccionario consulta una palabra o expresión en el diccionario
+func (h *OracionHandler) handleV1Diccionario(w http.ResponseWriter, r *http.Request) {
+	palabra := strings.TrimSpace(r.PathValue("word"))
+	if palabra == "" {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPeticionInvalida, "word must not be empty")
+		return
+	}
+
+	entrada, encontrada := parser.BuscarPalabra(palabra)
+	if !encontrada {
+		escribirErrorAPI(w, http.StatusNotFound, ErrorNoEncontrado, fmt.Sprintf("%q is not in the dictionary", palabra))
+		return
+	}
+
+	escribirJSON(w, http.StatusOK, models.RespuestaDiccionario{
+		Palabra:    entrada.Texto,
+		Encontrada: true,
+		Tipo:       entrada.Tipo,
+		Lema:       entrada.Metadata.Lema,
+		Forma:      entrada.Metadata.Forma,
+		SubTipo:    entrada.Metadata.SubTipo,
+	})
+}
+
+// handleV1Especificacion publica el documento OpenAPI
+func handleV1Especificacion(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(especificacionAPI)
+}
+
+// opcionesAPI interpreta el perfil y la puntuación estricta de una petición
+func (h *OracionHandler) opcionesAPI(nombre string, estricta bool) ([]parser.Opcion, parser.Perfil, error) {
+	perfil, err := parser.BuscarPerfil(nombre)
+	if err != nil {
+		return nil, perfil, err
+	}
+	return h.opcionesValidacion(perfil, estricta), perfil, nil
+}
+
+// validarTextoAPI valida un texto y construye la respuesta de la API, o el error
+// que impide validarlo
+func (h *OracionHandler) validarTextoAPI(validador *parser.Validator, texto string, perfil parser.Perfil, opciones []parser.Opcion) (*models.RespuestaValidacion, *models.ErrorAPI) {
+	if strings.TrimSpace(texto) == "" {
+		return nil, &models.ErrorAPI{Codigo: ErrorTextoVacio, Mensaje: "text must not be empty"}
+	}
+
+	analisis, err := h.analizarTexto(validador, texto, opciones)
+	if err != nil {
+		h.logger.Printf("Error in lexical analysis: %v", err)
+		return nil, &models.ErrorAPI{Codigo: ErrorInterno, Mensaje: "error in sentence analysis"}
+	}
+
+	resultado := analisis.resultado
+	respuesta := &models.RespuestaValidacion{
+		Texto:        texto,
+		Perfil:       perfil.Nombre,
+		Valida:       resultado.EsValida,
+		Mensaje:      resultado.Mensaje,
+		Explicacion:  resultado.Explicacion,
+		Correccion:   resultado.Correccion,
+		Oraciones:    oracionesAPI(analisis.segmentos),
+		Tokens:       tokensAPI(analisis.tokens),
+		Diagnosticos: make([]models.DiagnosticoAPI, 0, len(resultado.Diagnosticos)),
+	}
+	for _, d := range resultado.Diagnosticos {
+		respuesta.Diagnosticos = append(respuesta.Diagnosticos, models.NuevoDiagnosticoAPI(d))
+	}
+	return respuesta, nil
+}
+
+// oracionesAPI convierte los segmentos al formato de la API
+func oracionesAPI(segmentos []models.Segmento) []models.OracionAPI {
+	oraciones := make([]models.OracionAPI, 0, len(segmentos))
+	for _, s := range segmentos {
+		oraciones = append(oraciones, models.OracionAPI{Texto: s.Texto, Inicio: s.Inicio, Fin: s.Fin})
+	}
+	return oraciones
+}
+
+// tokensAPI convierte los tokens al formato de la API
+func tokensAPI(tokens []models.Token) []models.TokenAPI {
+	resultado := make([]models.TokenAPI, 0, len(tokens))
+	for _, t := range tokens {
+		resultado = append(resultado, models.NuevoTokenAPI(t))
+	}
+	return resultado
+}
+
+// leerPeticion decodifica el cuerpo JSON de la petición; si no es válido
+// responde con un error y devuelve false
+func leerPeticion(w http.ResponseWriter, r *http.Request, destino any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(destino); err != nil {
+		mensaje := "invalid JSON body: " + err.Error()
+		if errors.Is(err, io.EOF) {
+			mensaje = "request body is empty"
+		}
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPeticionInvalida, mensaje)
+		return false
+	}
+	return true
+}
+
+// estadoError devuelve el código HTTP de un error de validación
+func estadoError(err *models.ErrorAPI) int {
+	if err.Codigo == ErrorInterno {
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
+
+// escribirJSON responde con el valor codificado en JSON
+func escribirJSON(w http.ResponseWriter, estado int, valor any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(estado)
+	json.NewEncoder(w).Encode(valor)
+}
+
+// escribirErrorAPI responde con el cuerpo de error común de la API
+func escribirErrorAPI(w http.ResponseWriter, estado int, codigo, mensaje string) {
+	escribirJSON(w, estado, models.RespuestaError{Error: models.ErrorAPI{Codigo: codigo, Mensaje: mensaje}})
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+// nuevoMuxAPI crea un mux con las rutas de /api/v1 de un handler de prueba
+func nuevoMuxAPI(t *testing.T) *http.ServeMux {
+	t.Helper()
+	h, err := NewOracionHandler(models.NewValidadorConfig(), os.DirFS("templates"), log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("NewOracionHandler() unexpected error = %v", err)
+	}
+	mux := http.NewServeMux()
+	h.RegistrarAPIV1(mux)
+	return mux
+}
+
+// pedir envía una petición al mux y decodifica la respuesta JSON
+func pedir(t *testing.T, mux http.Handler, metodo, ruta string, cuerpo []byte) (int, any) {
+	t.Helper()
+	req := httptest.NewRequest(metodo, ruta, bytes.NewReader(cuerpo))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if tipo := rec.Header().Get("Content-Type"); tipo != "application/json" {
+		t.Errorf("%s %s Content-Type = %q, expected application/json", metodo, ruta, tipo)
+	}
+	var respuesta any
+	if err := json.Unmarshal(rec.Body.Bytes(), &respuesta); err != nil {
+		t.Fatalf("%s %s returned invalid JSON: %v\n%s", metodo, ruta, err, rec.Body.String())
+	}
+	return rec.Code, respuesta
+}
+
+// especificacion es el documento OpenAPI decodificado
+type especificacion map[string]any
+
+// obj accede a un objeto anidado del documento
+func obj(valor any, claves ...string) map[string]any {
+	if spec, ok := valor.(especificacion); ok {
+		valor = map[string]any(spec)
+	}
+	for _, clave := range claves {
+		m, _ := valor.(map[string]any)
+		valor = m[clave]
+	}
+	m, _ := valor.(map[string]any)
+	return m
+}
+
+// resolver sigue las referencias locales ("#/components/...")
+func (e especificacion) resolver(nodo map[string]any) map[string]any {
+	for {
+		ref, ok := nodo["$ref"].(string)
+		if !ok {
+			return nodo
+		}
+		nodo = obj(e, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...)
+	}
+}
+
+// validar comprueba que el valor cumple el esquema: tipos, enumeraciones,
+// propiedades obligatorias y que no haya propiedades sin documentar
+func (e especificacion) validar(t *testing.T, esquema map[string]any, valor any, ruta string) {
+	t.Helper()
+	esquema = e.resolver(esquema)
+
+	if enum, ok := esquema["enum"].([]any); ok {
+		encontrado := false
+		for _, permitido := range enum {
+			encontrado = encontrado || permitido == valor
+		}
+		if !encontrado {
+			t.Errorf("%s = %v, not in enum %v", ruta, valor, enum)
+		}
+	}
+
+	switch esquema["type"] {
+	case "object":
+		m, ok := valor.(map[string]any)
+		if !ok {
+			t.Errorf("%s = %v, expected an object", ruta, valor)
+			return
+		}
+		for _, requerida := range asLista(esquema["required"]) {
+			if _, existe := m[requerida.(string)]; !existe {
+				t.Errorf("%s lacks required property %q", ruta, requerida)
+			}
+		}
+		propiedades := obj(esquema, "properties")
+		if propiedades == nil {
+			return
+		}
+		for clave, v := range m {
+			sub, documentada := propiedades[clave].(map[string]any)
+			if !documentada {
+				t.Errorf("%s has undocumented property %q", ruta, clave)
+				continue
+			}
+			e.validar(t, sub, v, ruta+"."+clave)
+		}
+	case "array":
+		lista, ok := valor.([]any)
+		if !ok {
+			t.Errorf("%s = %v, expected an array", ruta, valor)
+			return
+		}
+		for i, v := range lista {
+			e.validar(t, obj(esquema, "items"), v, fmt.Sprintf("%s[%d]", ruta, i))
+		}
+	case "string":
+		if _, ok := valor.(string); !ok {
+			t.Errorf("%s = %v, expected a string", ruta, valor)
+		}
+	case "integer":
+		if n, ok := valor.(float64); !ok || n != math.Trunc(n) {
+			t.Errorf("%s = %v, expected an integer", ruta, valor)
+		}
+	case "boolean":
+		if _, ok := valor.(bool); !ok {
+			t.Errorf("%s = %v, expected a boolean", ruta, valor)
+		}
+	}
+}
+
+func asLista(valor any) []any {
+	lista, _ := valor.([]any)
+	return lista
+}
+
+// cargarEspecificacion obtiene el documento publicado por la API
+func cargarEspecificacion(t *testing.T, mux http.Handler) especificacion {
+	t.Helper()
+	estado, documento := pedir(t, mux, http.MethodGet, "/api/v1/openapi.json", nil)
+	if estado != http.StatusOK {
+		t.Fatalf("GET /api/v1/openapi.json status = %d", estado)
+	}
+	spec, _ := documento.(map[string]any)
+	if version, _ := spec["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		t.Fatalf("openapi version = %q, expected 3.x", version)
+	}
+	return spec
+}
+
+// TestOpenAPI comprueba que cada ruta documentada existe, responde a su ejemplo
+// con un estado documentado y que la respuesta cumple el esquema
+func TestOpenAPI(t *testing.T) {
+	mux := nuevoMuxAPI(t)
+	spec := cargarEspecificacion(t, mux)
+
+	// Las rutas registradas y las documentadas son las mismas
+	var registradas, documentadas []string
+	h := &OracionHandler{}
+	for _, ruta := range h.rutasAPIV1() {
+		registradas = append(registradas, strings.ToLower(ruta.metodo)+" "+ruta.patron)
+	}
+	for ruta, operaciones := range obj(spec, "paths") {
+		for metodo := range operaciones.(map[string]any) {
+			documentadas = append(documentadas, metodo+" "+ruta)
+		}
+	}
+	sort.Strings(registradas)
+	sort.Strings(documentadas)
+	if !reflect.DeepEqual(registradas, documentadas) {
+		t.Fatalf("registered routes %v, documented %v", registradas, documentadas)
+	}
+
+	for ruta, operaciones := range obj(spec, "paths") {
+		for metodo, o := range operaciones.(map[string]any) {
+			operacion := o.(map[string]any)
+			t.Run(metodo+" "+ruta, func(t *testing.T) {
+				url := ruta
+				for _, p := range asLista(operacion["parameters"]) {
+					parametro := p.(map[string]any)
+					url = strings.ReplaceAll(url, "{"+parametro["name"].(string)+"}", parametro["example"].(string))
+				}
+				var cuerpo []byte
+				if ejemplo, ok := obj(operacion, "requestBody", "content", "application/json")["example"]; ok {
+					cuerpo, _ = json.Marshal(ejemplo)
+				}
+
+				estado, respuesta := pedir(t, mux, strings.ToUpper(metodo), url, cuerpo)
+				if estado != http.StatusOK {
+					t.Fatalf("status = %d, expected 200: %v", estado, respuesta)
+				}
+				documentada := spec.resolver(obj(operacion, "responses", "200"))
+				spec.validar(t, obj(documentada, "content", "application/json", "schema"), respuesta, "response")
+
+				// Otro método recibe el error común documentado
+				otro := http.MethodDelete
+				estado, respuesta = pedir(t, mux, otro, url, nil)
+				if estado != http.StatusMethodNotAllowed {
+					t.Fatalf("%s status = %d, expected 405", otro, estado)
+				}
+				documentada = spec.resolver(obj(operacion, "responses", "405"))
+				spec.validar(t, obj(documentada, "content", "application/json", "schema"), respuesta, "error")
+			})
+		}
+	}
+
+	// Las enumeraciones siguen a los perfiles y tipos de palabra registrados
+	var perfiles, tipos []any
+	for _, p := range parser.Perfiles() {
+		perfiles = append(perfiles, p.Nombre)
+	}
+	for _, tipo := range models.TiposPalabra() {
+		tipos = append(tipos, tipo.String())
+	}
+	if enum := asLista(obj(spec, "components", "schemas", "Profile")["enum"]); !reflect.DeepEqual(enum, perfiles) {
+		t.Errorf("Profile enum = %v, expected %v", enum, perfiles)
+	}
+	if enum := asLista(obj(spec, "components", "schemas", "WordType")["enum"]); !reflect.DeepEqual(enum, tipos) {
+		t.Errorf("WordType enum = %v, expected %v", enum, tipos)
+	}
+}
+
+// TestAPIV1Errores comprueba los errores de la API y que su cuerpo sigue el esquema común
+func TestAPIV1Errores(t *testing.T) {
+	mux := nuevoMuxAPI(t)
+	spec := cargarEspecificacion(t, mux)
+	esquemaError := map[string]any{"$ref": "#/components/schemas/Error"}
+
+	tests := []struct {
+		name   string
+		metodo string
+		ruta   string
+		cuerpo string
+		estado int
+		codigo string
+	}{
+		{"invalid JSON", http.MethodPost, "/api/v1/validate", `{"text":`, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"empty body", http.MethodPost, "/api/v1/validate", ``, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"unknown field", http.MethodPost, "/api/v1/validate", `{"oracion":"I played"}`, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"unknown profile", http.MethodPost, "/api/v1/validate", `{"text":"I played","profile":"future"}`, http.StatusBadRequest, ErrorPerfilInvalido},
+		{"empty text", http.MethodPost, "/api/v1/validate", `{"text":"  "}`, http.StatusBadRequest, ErrorTextoVacio},
+		{"empty tokenize text", http.MethodPost, "/api/v1/tokenize", `{"text":""}`, http.StatusBadRequest, ErrorTextoVacio},
+		{"empty batch", http.MethodPost, "/api/v1/validate/batch", `{"texts":[]}`, http.StatusBadRequest, ErrorLoteInvalido},
+		{"batch too large", http.MethodPost, "/api/v1/validate/batch", `{"texts":[` + strings.Repeat(`"a",`, 100) + `"a"]}`, http.StatusBadRequest, ErrorLoteInvalido},
+		{"unknown word", http.MethodGet, "/api/v1/dictionary/zzzz", ``, http.StatusNotFound, ErrorNoEncontrado},
+		{"unknown route", http.MethodGet, "/api/v1/nothing", ``, http.StatusNotFound, ErrorNoEncontrado},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estado, respuesta := pedir(t, mux, tt.metodo, tt.ruta, []byte(tt.cuerpo))
+			if estado != tt.estado {
+				t.Errorf("status = %d, expected %d", estado, tt.estado)
+			}
+			spec.validar(t, esquemaError, respuesta, "error")
+			if codigo := obj(respuesta, "error")["code"]; codigo != tt.codigo {
+				t.Errorf("error code = %v, expected %q", codigo, tt.codigo)
+			}
+		})
+	}
+}
+
+// TestAPIV1Validar comprueba el contenido de las respuestas de validación y de lote
+func TestAPIV1Validar(t *testing.T) {
+	mux := nuevoMuxAPI(t)
+
+	_, respuesta := pedir(t, mux, http.MethodPost, "/api/v1/validate", []byte(`{"text":"They was happy. I played football."}`))
+	if valida := obj(respuesta)["valid"]; valida != false {
+		t.Errorf("valid = %v, expected false", valida)
+	}
+	if correccion := obj(respuesta)["correction"]; correccion != "They were happy. I played football." {
+		t.Errorf("correction = %v", correccion)
+	}
+	diagnosticos := asLista(obj(respuesta)["diagnostics"])
+	if len(diagnosticos) != 1 || obj(diagnosticos[0])["rule"] != parser.ReglaConcordanciaWasWere {
+		t.Fatalf("diagnostics = %v, expected one %s", diagnosticos, parser.ReglaConcordanciaWasWere)
+	}
+	if span := obj(diagnosticos[0], "span"); span["byte_start"] != 5.0 || span["byte_end"] != 8.0 {
+		t.Errorf("span = %v, expected bytes [5, 8)", span)
+	}
+	if oraciones := asLista(obj(respuesta)["sentences"]); len(oraciones) != 2 {
+		t.Errorf("sentences = %v, expected 2", oraciones)
+	}
+
+	_, respuesta = pedir(t, mux, http.MethodPost, "/api/v1/validate/batch", []byte(`{"texts":["I played football.",""]}`))
+	resultados := asLista(obj(respuesta)["results"])
+	if len(resultados) != 2 {
+		t.Fatalf("results = %v, expected 2", resultados)
+	}
+	if obj(resultados[0], "result")["valid"] != true || obj(resultados[0], "error") != nil {
+		t.Errorf("results[0] = %v, expected a valid result", resultados[0])
+	}
+	if obj(resultados[1], "error")["code"] != ErrorTextoVacio || obj(resultados[1], "result") != nil {
+		t.Errorf("results[1] = %v, expected an %s error", resultados[1], ErrorTextoVacio)
+	}
+
+	_, respuesta = pedir(t, mux, http.MethodGet, "/api/v1/dictionary/ate", nil)
+	if entrada := obj(respuesta); entrada["type"] != "simple_verb" || entrada["lemma"] != "eat" {
+		t.Errorf("dictionary entry = %v, expected the simple verb 'eat'", entrada)
+	}
+
+	// words.json escribe estas entradas con mayúscula
+	for _, palabra := range []string{"I", "John", "Cartagena", "cartagena"} {
+		estado, _ := pedir(t, mux, http.MethodGet, "/api/v1/dictionary/"+palabra, nil)
+		if estado != http.StatusOK {
+			t.Errorf("GET /api/v1/dictionary/%s status = %d, expected 200", palabra, estado)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+// HandleAPIValidacionLote valida muchas oraciones en una sola petición. Una
+// oración que no se puede validar recibe un error en su resultado sin impedir
+// que se validen las demás. Los errores de la petición tienen el formato de la
+// API v1.
+func (h *OracionHandler) HandleAPIValidacionLote(w http.ResponseWriter, r *http.Request) {
+	soloMetodo(http.MethodPost, h.validarLote)(w, r)
+}
+
+// validarLote atiende HandleAPIValidacionLote una vez comprobado el método
+func (h *OracionHandler) validarLote(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionLoteOraciones
+	if !leerPeticion(w, r, &peticion) {
+		return
+	}
+
+	perfil, err := parser.BuscarPerfil(peticion.Perfil)
+	if err != nil {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorPerfilInvalido, err.Error())
+		return
+	}
+
+	oraciones, segmentos, err := h.oracionesLote(peticion)
+	if err != nil {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorLoteInvalido, err.Error())
+		return
+	}
+	if len(oraciones) > h.config.MaxLote {
+		escribirErrorAPI(w, http.StatusBadRequest, ErrorLoteInvalido, fmt.Sprintf("a batch accepts at most %d sentences", h.config.MaxLote))
+		return
+	}
+
+	opciones := h.opcionesValidacion(perfil, peticion.PuntuacionEstricta)
+	validador := parser.ValidadorActual()
+	respuesta := models.RespuestaLoteOraciones{Resultados: make([]models.ResultadoLoteOracion, len(oraciones))}
+	procesados := make([]*models.ResultadoOracion, len(oraciones))
+	err = procesarEnParalelo(r.Context(), len(oraciones), h.config.Trabajadores, func(i int) {
+		resultado, procesado := h.validarOracionLote(validador, oraciones[i], opciones)
+		resultado.Indice = i
+		if segmentos != nil {
+			resultado.Segmento = &segmentos[i]
+		}
+		respuesta.Resultados[i], procesados[i] = resultado, procesado
+	})
+	if err != nil {
+		if estado, codigo, mensaje, responder := h.cancelacion(r, err); responder {
+			escribirErrorAPI(w, estado, codigo, mensaje)
+		}
+		return
+	}
+	respuesta.Estadisticas = h.estadisticasLote(len(oraciones), procesados)
+
+	escribirJSON(w, http.StatusOK, respuesta)
+}
+
+// oracionesLote obtiene las oraciones de la petición; si llega un párrafo
+// también devuelve la posición de cada oración en él
+func (h *OracionHandler) oracionesLote(peticion models.PeticionLoteOraciones) ([]string, []models.Segmento, error) {
+	switch {
+	case len(peticion.Oraciones) > 0 && peticion.Parrafo != "":
+		return nil, nil, fmt.Errorf("send either oraciones or parrafo, not both")
+	case len(peticion.Oraciones) > 0:
+		return peticion.Oraciones, nil, nil
+	}
+
+	segmentos := h.procesarEntrada(peticion.Parrafo)
+	if len(segmentos) == 0 {
+		return nil, nil, fmt.Errorf("there are no sentences to validate")
+	}
+	oraciones := make([]string, len(segmentos))
+	for i, segmento := range segmentos {
+		oraciones[i] = segmento.Texto
+	}
+	return oraciones, segmentos, nil
+}
+
+// validarOracionLote valida una oración del lote. Se limpia como las del
+// formulario y los diagnósticos señalan la oración recibida. El segundo
+// resultado es nil si la oración no se pudo validar y no cuenta en las
+// estadísticas.
+func (h *OracionHandler) validarOracionLote(validador *parser.Validator, oracion string, opciones []parser.Opcion) (models.ResultadoLoteOracion, *models.ResultadoOracion) {
+	resultado := models.ResultadoLoteOracion{Oracion: oracion}
+	if strings.TrimSpace(oracion) == "" {
+		resultado.Error = "the sentence is empty"
+		return resultado, nil
+	}
+
+	texto, origen := oracion, []int(nil)
+	if h.config.LimpiarEntrada {
+		texto, origen = h.limpiarOracion(oracion)
+	}
+
+	var procesado models.ResultadoOracion
+	if err := h.validarLongitud(texto); err != nil {
+		procesado = models.ResultadoOracion{
+			Oracion:     texto,
+			Mensaje:     "Invalid length",
+			Explicacion: err.Error(),
+			Diagnosticos: []models.Diagnostic{{
+				Regla:     parser.ReglaLongitud,
+				Severidad: models.SeveridadError,
+				Mensaje:   err.Error(),
+			}},
+		}
+	} else {
+		analisis, err := h.analizarTexto(validador, texto, opciones)
+		if err != nil {
+			h.logger.Printf("Error in lexical analysis: %v", err)
+			resultado.Error = "error in sentence analysis"
+			return resultado, nil
+		}
+		procesado = analisis.resultado
+		if origen != nil {
+			parser.ReubicarEnOriginal(procesado.Diagnosticos, oracion, origen)
+		}
+	}
+
+	resultado.EsValida = procesado.EsValida
+	resultado.Mensaje = procesado.Mensaje
+	resultado.Explicacion = procesado.Explicacion
+	resultado.Correccion = procesado.Correccion
+	resultado.Diagnosticos = procesado.Diagnosticos
+	return resultado, &procesado
+}
+
+// estadisticasLote resume el lote a partir de las oraciones que se validaron
+// (las posiciones nil son las que fallaron)
+func (h *OracionHandler) estadisticasLote(total int, procesados []*models.ResultadoOracion) models.EstadisticasLote {
+	var validados []models.ResultadoOracion
+	for _, p := range procesados {
+		if p != nil {
+			validados = append(validados, *p)
+		}
+	}
+
+	stats := h.calcularEstadisticas(validados)
+	validas := stats.TiposValidos["Valids"]
+	return models.EstadisticasLote{
+		Total:           total,
+		Validas:         validas,
+		Invalidas:       len(validados) - validas,
+		Fallidas:        total - len(validados),
+		PorcentajeExito: stats.PorcentajeExito,
+		ErroresComunes:  stats.ErroresComunes,
+	}
+}
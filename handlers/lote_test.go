@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+func TestHandleAPIValidacionLote(t *testing.T) {
+	config := models.NewValidadorConfig()
+	config.MaxLote = 3
+	h := handlerDePrueba(config)
+
+	enviar := func(cuerpo string) (*httptest.ResponseRecorder, models.RespuestaLoteOraciones) {
+		rec := httptest.NewRecorder()
+		h.HandleAPIValidacionLote(rec, httptest.NewRequest(http.MethodPost, "/api/validar/batch", strings.NewReader(cuerpo)))
+		var respuesta models.RespuestaLoteOraciones
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&respuesta); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+		}
+		return rec, respuesta
+	}
+
+	t.Run("array with partial failure", func(t *testing.T) {
+		rec, respuesta := enviar(`{"oraciones":["I played football.","They was happy."," "]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected 200: %s", rec.Code, rec.Body.String())
+		}
+		if len(respuesta.Resultados) != 3 {
+			t.Fatalf("results = %d, expected 3", len(respuesta.Resultados))
+		}
+		for i, r := range respuesta.Resultados {
+			if r.Indice != i {
+				t.Errorf("results[%d].indice = %d", i, r.Indice)
+			}
+		}
+		if !respuesta.Resultados[0].EsValida || respuesta.Resultados[1].EsValida {
+			t.Errorf("validity = %v, %v, expected true, false", respuesta.Resultados[0].EsValida, respuesta.Resultados[1].EsValida)
+		}
+		if respuesta.Resultados[2].Error == "" {
+			t.Errorf("results[2] should carry an error for the empty sentence")
+		}
+
+		esperadas := models.EstadisticasLote{Total: 3, Validas: 1, Invalidas: 1, Fallidas: 1, PorcentajeExito: 50,
+			ErroresComunes: map[string]int{parser.ReglaConcordanciaWasWere: 1}}
+		if got := respuesta.Estadisticas; got.Total != esperadas.Total || got.Validas != esperadas.Validas ||
+			got.Invalidas != esperadas.Invalidas || got.Fallidas != esperadas.Fallidas ||
+			got.PorcentajeExito != esperadas.PorcentajeExito || got.ErroresComunes[parser.ReglaConcordanciaWasWere] != 1 {
+			t.Errorf("statistics = %+v, expected %+v", got, esperadas)
+		}
+	})
+
+	t.Run("paragraph is segmented", func(t *testing.T) {
+		parrafo := "I played football. She ate pizza yesterday."
+		rec, respuesta := enviar(`{"parrafo":"` + parrafo + `"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected 200: %s", rec.Code, rec.Body.String())
+		}
+		if len(respuesta.Resultados) != 2 {
+			t.Fatalf("results = %d, expected 2", len(respuesta.Resultados))
+		}
+		segundo := respuesta.Resultados[1]
+		if segundo.Segmento == nil || parrafo[segundo.Segmento.Inicio:segundo.Segmento.Fin] != segundo.Oracion {
+			t.Errorf("results[1] segment = %+v, expected the position of %q", segundo.Segmento, segundo.Oracion)
+		}
+	})
+
+	t.Run("sentences are cleaned like the form", func(t *testing.T) {
+		invalida := "They   *was* happy."
+		rec, respuesta := enviar(`{"oraciones":["I *played* football.","` + invalida + `"]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected 200: %s", rec.Code, rec.Body.String())
+		}
+		if !respuesta.Resultados[0].EsValida {
+			t.Errorf("results[0] = %+v, expected valid once cleaned", respuesta.Resultados[0])
+		}
+		resultado := respuesta.Resultados[1]
+		if resultado.EsValida || len(resultado.Diagnosticos) == 0 {
+			t.Fatalf("results[1] = %+v, expected the agreement error", resultado)
+		}
+		// El diagnóstico señala "was" en la oración recibida
+		if rango := resultado.Diagnosticos[0].Rango; invalida[rango.ByteInicio:rango.ByteFin] != "was" {
+			t.Errorf("diagnostic span = %q, expected %q", invalida[rango.ByteInicio:rango.ByteFin], "was")
+		}
+	})
+
+	errores := []struct {
+		name   string
+		metodo string
+		cuerpo string
+		estado int
+		codigo string
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed, ErrorMetodo},
+		{"invalid JSON", http.MethodPost, `{"oraciones":`, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"unknown field", http.MethodPost, `{"oraciones":["I played."],"profile":"past-negative"}`, http.StatusBadRequest, ErrorPeticionInvalida},
+		{"array and paragraph", http.MethodPost, `{"oraciones":["I played."],"parrafo":"I played."}`, http.StatusBadRequest, ErrorLoteInvalido},
+		{"nothing to validate", http.MethodPost, `{}`, http.StatusBadRequest, ErrorLoteInvalido},
+		{"unknown profile", http.MethodPost, `{"oraciones":["I played."],"perfil":"future"}`, http.StatusBadRequest, ErrorPerfilInvalido},
+		{"over the limit", http.MethodPost, `{"oraciones":["a","b","c","d"]}`, http.StatusBadRequest, ErrorLoteInvalido},
+	}
+	for _, tt := range errores {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.HandleAPIValidacionLote(rec, httptest.NewRequest(tt.metodo, "/api/validar/batch", strings.NewReader(tt.cuerpo)))
+			if rec.Code != tt.estado {
+				t.Errorf("status = %d, expected %d", rec.Code, tt.estado)
+			}
+			var respuesta models.RespuestaError
+			if err := json.NewDecoder(rec.Body).Decode(&respuesta); err != nil || respuesta.Error.Codigo != tt.codigo {
+				t.Errorf("body = %+v, %v, expected the %s error code", respuesta, err, tt.codigo)
+			}
+		})
+	}
+}
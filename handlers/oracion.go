@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log"
 	"net/http"
-	"path/filepath"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 	"validar_oraciones/models"
 	parser "validar_oraciones/parser"
 )
@@ -17,11 +21,13 @@ type OracionHandler struct {
 	config    models.ValidadorConfig
 	templates *template.Template
 	logger    *log.Logger
+	sesiones  sync.Map // Sesiones de validación en vivo abiertas (*sesionVivo por identificador)
 }
 
-// NewOracionHandler crea una nueva instancia del manejador
-func NewOracionHandler(config models.ValidadorConfig, logger *log.Logger) (*OracionHandler, error) {
-	tmpl, err := template.ParseFiles(filepath.Join("templates", "index.html"))
+// NewOracionHandler crea una nueva instancia del manejador; plantillas es el
+// directorio que contiene index.html
+func NewOracionHandler(config models.ValidadorConfig, plantillas fs.FS, logger *log.Logger) (*OracionHandler, error) {
+	tmpl, err := template.ParseFS(plantillas, "index.html")
 	if err != nil {
 		return nil, err
 	}
@@ -33,17 +39,39 @@ func NewOracionHandler(config models.ValidadorConfig, logger *log.Logger) (*Orac
 	}, nil
 }
 
-// limpiarOracion elimina caracteres no deseados y espacios extra
-func (h *OracionHandler) limpiarOracion(oracion string) string {
-	oracion = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == ' ' || r == '.' || r == ',' {
-			return r
+// limpiarOracion elimina caracteres no deseados y espacios extra. También
+// devuelve, para cada byte de la oración limpia, su posición en la original,
+// de modo que los diagnósticos puedan señalar lo que escribió el estudiante.
+func (h *OracionHandler) limpiarOracion(oracion string) (string, []int) {
+	var limpia strings.Builder
+	var origen []int
+	espacio := -1 // Último espacio pendiente de escribir
+
+	for i, r := range oracion {
+		// Los apóstrofos se conservan para las contracciones ("didn't", "I'm")
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || strings.ContainsRune(" .,;:?!'’\"()", r)) {
+			continue
+		}
+
+		// Normalizar espacios
+		if r == ' ' {
+			if limpia.Len() > 0 {
+				espacio = i
+			}
+			continue
 		}
-		return -1
-	}, oracion)
+		if espacio != -1 {
+			limpia.WriteByte(' ')
+			origen = append(origen, espacio)
+			espacio = -1
+		}
+		limpia.WriteRune(r)
+		for j := 0; j < utf8.RuneLen(r); j++ {
+			origen = append(origen, i+j)
+		}
+	}
 
-	// Normalizar espacios
-	return strings.Join(strings.Fields(oracion), " ")
+	return limpia.String(), origen
 }
 
 // validarLongitud verifica que la oración cumpla con los límites de palabras
@@ -88,6 +116,12 @@ func (h *OracionHandler) handlePost(w http.ResponseWriter, r *http.Request) {
 	input := r.FormValue("oraciones")
 	oraciones := h.procesarEntrada(input)
 
+	perfil, err := parser.BuscarPerfil(r.FormValue("profile"))
+	if err != nil {
+		h.renderTemplate(w, models.PageVariables{ErrorMessage: "Please choose a valid exercise profile."})
+		return
+	}
+
 	if len(oraciones) > h.config.MaxOraciones {
 		vars := models.PageVariables{
 			ErrorMessage: fmt.Sprintf("Please enter a maximum of %d sentences.", h.config.MaxOraciones),
@@ -96,80 +130,128 @@ func (h *OracionHandler) handlePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resultados := h.validarOraciones(oraciones)
+	estricta := r.FormValue("strict_punctuation") != ""
+	resultados, err := h.validarOraciones(r.Context(), oraciones, h.opcionesValidacion(perfil, estricta))
+	if err != nil {
+		if estado, _, mensaje, responder := h.cancelacion(r, err); responder {
+			http.Error(w, mensaje, estado)
+		}
+		return
+	}
 	stats := h.calcularEstadisticas(resultados)
 
 	vars := models.PageVariables{
-		Oraciones:        resultados,
-		TotalOraciones:   len(resultados),
-		OracionesValidas: stats.TiposValidos["Valids"],
-		ShowResults:      true,
-		Estadisticas:     stats,
+		Oraciones:          resultados,
+		TotalOraciones:     len(resultados),
+		OracionesValidas:   stats.TiposValidos["Valids"],
+		ShowResults:        true,
+		Estadisticas:       stats,
+		Perfil:             perfil.Nombre,
+		PuntuacionEstricta: estricta,
+		Leyenda:            leyendaResultados(resultados),
 	}
 
 	h.renderTemplate(w, vars)
 }
 
-// procesarEntrada divide y limpia las oraciones de entrada
-func (h *OracionHandler) procesarEntrada(input string) []string {
-	oraciones := strings.Split(input, ".")
-	var processed []string
+// procesarEntrada divide la entrada en oraciones; cada segmento conserva el
+// texto tal como se escribió y su posición en la entrada
+func (h *OracionHandler) procesarEntrada(input string) []models.Segmento {
+	var processed []models.Segmento
 
-	for _, o := range oraciones {
-		if o = strings.TrimSpace(o); o != "" {
-			if h.config.LimpiarEntrada {
-				o = h.limpiarOracion(o)
-			}
-			if o != "" {
-				processed = append(processed, o)
-			}
+	for _, segmento := range parser.SegmentarOraciones(input) {
+		// Los signos sueltos ("...") no forman una oración
+		if strings.IndexFunc(segmento.Texto, unicode.IsLetter) != -1 {
+			processed = append(processed, segmento)
 		}
 	}
 
 	return processed
 }
 
-// validarOraciones procesa y valida cada oración usando el análisis léxico
-func (h *OracionHandler) validarOraciones(oraciones []string) []models.ResultadoOracion {
-	var resultados []models.ResultadoOracion
+// validarOraciones valida las oraciones en paralelo; los resultados siguen el
+// orden de los segmentos
+func (h *OracionHandler) validarOraciones(ctx context.Context, segmentos []models.Segmento, opciones []parser.Opcion) ([]models.ResultadoOracion, error) {
+	validador := parser.ValidadorActual()
+	resultados := make([]models.ResultadoOracion, len(segmentos))
+	err := procesarEnParalelo(ctx, len(segmentos), h.config.Trabajadores, func(i int) {
+		resultados[i] = h.validarSegmento(validador, segmentos[i], opciones)
+	})
+	return resultados, err
+}
 
-	for _, oracion := range oraciones {
-		// Limpiar la oración antes de validarla
-		oracion = h.limpiarOracion(oracion)
+// validarSegmento procesa y valida una oración usando el análisis léxico
+func (h *OracionHandler) validarSegmento(validador *parser.Validator, segmento models.Segmento, opciones []parser.Opcion) models.ResultadoOracion {
+	// Limpiar la oración antes de validarla
+	oracion, origen := segmento.Texto, []int(nil)
+	if h.config.LimpiarEntrada {
+		oracion, origen = h.limpiarOracion(oracion)
+	}
 
-		if err := h.validarLongitud(oracion); err != nil {
-			resultados = append(resultados, models.ResultadoOracion{
-				Oracion:     oracion,
-				EsValida:    false,
-				Mensaje:     "Invalid length",
-				Explicacion: err.Error(),
-			})
-			continue
+	if err := h.validarLongitud(oracion); err != nil {
+		return models.ResultadoOracion{
+			Oracion:     oracion,
+			Segmento:    segmento,
+			EsValida:    false,
+			Mensaje:     "Invalid length",
+			Explicacion: err.Error(),
+			Diagnosticos: []models.Diagnostic{{
+				Regla:     parser.ReglaLongitud,
+				Severidad: models.SeveridadError,
+				Mensaje:   err.Error(),
+			}},
 		}
+	}
 
-		// Análisis léxico
-		tokens, err := parser.AnalizarLexico(oracion)
-		if err != nil {
-			resultados = append(resultados, models.ResultadoOracion{
-				Oracion:     oracion,
-				EsValida:    false,
-				Mensaje:     "Error in lexical analysis",
-				Explicacion: err.Error(),
-			})
-			continue
-		}
+	// Análisis léxico y validación de la estructura de la oración
+	tokens, _ := validador.AnalizarLexico(oracion)
+	diagnosticos := validador.DiagnosticarOracion(oracion, opciones...)
+	resultado := nuevoResultado(oracion, diagnosticos, opciones)
+	resultado.Segmento = segmento
 
-		// Validar la estructura de la oración basada en los tokens
-		validez, explicacion := parser.ValidarTokens(tokens)
-		resultados = append(resultados, models.ResultadoOracion{
-			Oracion:     oracion,
-			EsValida:    validez == "Valid",
-			Mensaje:     validez,
-			Explicacion: explicacion,
-		})
+	// Las posiciones señalan el texto escrito, no la oración limpia
+	if origen != nil {
+		parser.ReubicarTokensEnOriginal(tokens, segmento.Texto, origen)
+		parser.ReubicarEnOriginal(resultado.Diagnosticos, segmento.Texto, origen)
 	}
+	resultado.Tokens = tokens
+	resultado.Fragmentos = fragmentosOracion(segmento.Texto, tokens, resultado.Diagnosticos)
+	return resultado
+}
 
-	return resultados
+// opcionesValidacion traduce la configuración del handler, el perfil de
+// ejercicio y la puntuación estricta pedidos a opciones del validador
+func (h *OracionHandler) opcionesValidacion(perfil parser.Perfil, estricta bool) []parser.Opcion {
+	opciones := []parser.Opcion{parser.ConPerfil(perfil)}
+	if h.config.TodosLosErrores {
+		opciones = append(opciones, parser.ConTodosLosErrores())
+	}
+	if h.config.PuntuacionEstricta || estricta {
+		opciones = append(opciones, parser.ConPuntuacionEstricta())
+	}
+	return opciones
+}
+
+// nuevoResultado construye el resultado de una oración a partir de sus diagnósticos
+func nuevoResultado(oracion string, diagnosticos []models.Diagnostic, opciones []parser.Opcion) models.ResultadoOracion {
+	resultado := models.ResultadoOracion{
+		Oracion:      oracion,
+		EsValida:     parser.EsValida(diagnosticos),
+		Mensaje:      "Valid",
+		Explicacion:  parser.ExplicacionValida(opciones...),
+		Diagnosticos: diagnosticos,
+	}
+
+	if !resultado.EsValida {
+		resultado.Mensaje = "Invalid"
+		resultado.Explicacion = diagnosticos[0].Mensaje
+	}
+
+	if corregida := parser.AplicarSugerencias(oracion, diagnosticos); corregida != oracion {
+		resultado.Correccion = corregida
+	}
+
+	return resultado
 }
 
 // calcularEstadisticas genera estadísticas sobre los resultados
@@ -185,7 +267,11 @@ func (h *OracionHandler) calcularEstadisticas(resultados []models.ResultadoOraci
 			validas++
 			stats.TiposValidos["Valids"]++
 		} else {
-			stats.ErroresComunes[r.Mensaje]++
+			for _, d := range r.Diagnosticos {
+				if d.Severidad == models.SeveridadError {
+					stats.ErroresComunes[d.Regla]++
+				}
+			}
 		}
 	}
 
@@ -198,6 +284,13 @@ func (h *OracionHandler) calcularEstadisticas(resultados []models.ResultadoOraci
 
 // renderTemplate renderiza la plantilla con las variables dadas
 func (h *OracionHandler) renderTemplate(w http.ResponseWriter, vars models.PageVariables) {
+	for _, perfil := range parser.Perfiles() {
+		vars.Perfiles = append(vars.Perfiles, models.OpcionPerfil{Nombre: perfil.Nombre, Descripcion: perfil.Descripcion})
+	}
+	if vars.Perfil == "" {
+		vars.Perfil = parser.PerfilPorDefecto().Nombre
+	}
+
 	if err := h.templates.Execute(w, vars); err != nil {
 		h.logger.Println("Error rendering template:", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -210,10 +303,61 @@ func (h *OracionHandler) handleError(w http.ResponseWriter, message string, err
 	http.Error(w, message, http.StatusBadRequest)
 }
 
+// analisisTexto reúne el análisis de un texto que puede contener varias oraciones
+type analisisTexto struct {
+	segmentos []models.Segmento
+	tokens    []models.Token
+	resultado models.ResultadoOracion
+}
+
+// tokenizarTexto divide el texto en oraciones y las analiza; las posiciones de
+// los tokens apuntan al texto completo
+func (h *OracionHandler) tokenizarTexto(validador *parser.Validator, texto string) ([]models.Segmento, []models.Token, error) {
+	segmentos := parser.SegmentarOraciones(texto)
+	if len(segmentos) == 0 {
+		segmentos = []models.Segmento{{Texto: texto}}
+	}
+
+	var tokens []models.Token
+	for _, segmento := range segmentos {
+		tokensOracion, err := validador.AnalizarLexico(segmento.Texto)
+		if err != nil {
+			return nil, nil, err
+		}
+		parser.UbicarTokensEnTexto(tokensOracion, texto, segmento)
+		tokens = append(tokens, tokensOracion...)
+	}
+	return segmentos, tokens, nil
+}
+
+// analizarTexto valida cada oración del texto; los diagnósticos de cada una se
+// ubican en el texto completo
+func (h *OracionHandler) analizarTexto(validador *parser.Validator, texto string, opciones []parser.Opcion) (analisisTexto, error) {
+	segmentos, tokens, err := h.tokenizarTexto(validador, texto)
+	if err != nil {
+		return analisisTexto{}, err
+	}
+
+	var diagnosticos []models.Diagnostic
+	for _, segmento := range segmentos {
+		diagnosticosOracion := validador.DiagnosticarOracion(segmento.Texto, opciones...)
+		parser.UbicarEnTexto(diagnosticosOracion, texto, segmento)
+		diagnosticos = append(diagnosticos, diagnosticosOracion...)
+	}
+
+	return analisisTexto{
+		segmentos: segmentos,
+		tokens:    tokens,
+		resultado: nuevoResultado(texto, diagnosticos, opciones),
+	}, nil
+}
+
 // HandleAPIValidation maneja la validación de oraciones a través de la API
 func (h *OracionHandler) HandleAPIValidation(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		Oracion string `json:"oracion"`
+		Oracion            string `json:"oracion"`
+		Perfil             string `json:"profile"`
+		PuntuacionEstricta bool   `json:"strict_punctuation"`
 	}
 
 	// Decodificar el cuerpo de la solicitud
@@ -222,29 +366,56 @@ func (h *OracionHandler) HandleAPIValidation(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Análisis léxico
-	tokens, err := parser.AnalizarLexico(request.Oracion)
+	perfil, err := parser.BuscarPerfil(request.Perfil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	analisis, err := h.analizarTexto(parser.ValidadorActual(), request.Oracion, h.opcionesValidacion(perfil, request.PuntuacionEstricta))
 	if err != nil {
 		h.logger.Printf("Error in lexical analysis: %v", err)
 		http.Error(w, "Error in sentence analysis", http.StatusInternalServerError)
 		return
 	}
-
-	// Validar la estructura de la oración basada en los tokens
-	validez, explicacion := parser.ValidarTokens(tokens)
+	resultado := analisis.resultado
 
 	response := struct {
-		Tokens      []models.Token `json:"tokens"`
-		EsValida    bool           `json:"es_valida"`
-		Mensaje     string         `json:"mensaje"`
-		Explicacion string         `json:"explicacion"`
+		Tokens       []models.Token      `json:"tokens"`
+		Oraciones    []models.Segmento   `json:"oraciones"`
+		EsValida     bool                `json:"es_valida"`
+		Mensaje      string              `json:"mensaje"`
+		Explicacion  string              `json:"explicacion"`
+		Correccion   string              `json:"correccion,omitempty"`
+		Diagnosticos []models.Diagnostic `json:"diagnosticos"`
 	}{
-		Tokens:      tokens,
-		EsValida:    validez == "Valid",
-		Mensaje:     validez,
-		Explicacion: explicacion,
+		Tokens:       analisis.tokens,
+		Oraciones:    analisis.segmentos,
+		EsValida:     resultado.EsValida,
+		Mensaje:      resultado.Mensaje,
+		Explicacion:  resultado.Explicacion,
+		Correccion:   resultado.Correccion,
+		Diagnosticos: resultado.Diagnosticos,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// HandleAPITipos lista los tipos de palabra con los nombres que usa la API
+func (h *OracionHandler) HandleAPITipos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var tipos []models.DescripcionTipo
+	for _, tipo := range models.TiposPalabra() {
+		tipos = append(tipos, models.DescripcionTipo{Nombre: tipo.String(), Valor: uint8(tipo)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Tipos []models.DescripcionTipo `json:"types"`
+	}{tipos})
+}
@@ -1 +1,100 @@
 package handlers
+
+import (
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+// TestMain ejecuta las pruebas desde la raíz del repositorio, donde están
+// words.json y las plantillas
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// handlerDePrueba crea un handler sin plantillas que descarta el log
+func handlerDePrueba(config models.ValidadorConfig) *OracionHandler {
+	return &OracionHandler{config: config, logger: log.New(io.Discard, "", 0)}
+}
+
+func TestLimpiarOracion(t *testing.T) {
+	h := handlerDePrueba(models.NewValidadorConfig())
+	original := "  They  was #happy ¡"
+
+	limpia, origen := h.limpiarOracion(original)
+	if limpia != "They was happy" {
+		t.Fatalf("limpiarOracion() = %q, expected %q", limpia, "They was happy")
+	}
+	if len(origen) != len(limpia) {
+		t.Fatalf("limpiarOracion() returned %d positions for %d bytes", len(origen), len(limpia))
+	}
+
+	diagnosticos := []models.Diagnostic{
+		{Rango: models.Rango{PalabraInicio: 1, PalabraFin: 2, ByteInicio: 5, ByteFin: 8}},
+		{Rango: models.Rango{PalabraInicio: 1, PalabraFin: 3, ByteInicio: 5, ByteFin: 14}},
+	}
+	parser.ReubicarEnOriginal(diagnosticos, original, origen)
+
+	esperados := []struct {
+		texto                     string
+		palabraInicio, palabraFin int
+	}{
+		{"was", 1, 2},
+		{"was #happy", 1, 3},
+	}
+	for i, e := range esperados {
+		rango := diagnosticos[i].Rango
+		if got := original[rango.ByteInicio:rango.ByteFin]; got != e.texto {
+			t.Errorf("range %d points to %q, expected %q", i, got, e.texto)
+		}
+		if rango.PalabraInicio != e.palabraInicio || rango.PalabraFin != e.palabraFin {
+			t.Errorf("range %d words = [%d, %d), expected [%d, %d)", i, rango.PalabraInicio, rango.PalabraFin, e.palabraInicio, e.palabraFin)
+		}
+		if rango.RunaInicio != rango.ByteInicio || rango.RunaFin != rango.ByteFin {
+			t.Errorf("range %d runes = [%d, %d), expected ASCII offsets", i, rango.RunaInicio, rango.RunaFin)
+		}
+	}
+}
+
+func TestFragmentosOracion(t *testing.T) {
+	texto := "They  wasn't happy."
+	tokens := []models.Token{
+		{Tipo: models.TipoSujeto, ByteInicio: 0, ByteFin: 4},
+		{Tipo: models.TipoVerboAuxiliar, ByteInicio: 6, ByteFin: 12},
+		{Tipo: models.TipoNegativo, ByteInicio: 6, ByteFin: 12}, // "not" de la contracción
+		{Tipo: models.TipoAdjetivo, ByteInicio: 13, ByteFin: 18},
+		{Tipo: models.TipoPuntuacion, ByteInicio: 18, ByteFin: 19},
+	}
+	diagnosticos := []models.Diagnostic{
+		{Mensaje: "Use 'weren't'.", Rango: models.Rango{ByteInicio: 6, ByteFin: 12}},
+		{Mensaje: "Missing word.", Rango: models.Rango{ByteInicio: 13, ByteFin: 13}},
+	}
+
+	esperados := []models.Fragmento{
+		{Texto: "They", Clase: "subject", Categoria: "Subject"},
+		{Texto: "  "},
+		{Texto: "wasn't", Clase: "auxiliary_verb", Categoria: "Auxiliary verb", Errores: []string{"Use 'weren't'."}},
+		{Texto: " "},
+		{Texto: "happy", Clase: "adjective", Categoria: "Adjective", Errores: []string{"Missing word."}},
+		{Texto: ".", Clase: "punctuation", Categoria: "Punctuation"},
+	}
+	if fragmentos := fragmentosOracion(texto, tokens, diagnosticos); !reflect.DeepEqual(fragmentos, esperados) {
+		t.Errorf("fragmentosOracion() = %+v, expected %+v", fragmentos, esperados)
+	}
+
+	leyenda := leyendaResultados([]models.ResultadoOracion{{Fragmentos: esperados}})
+	var clases []string
+	for _, c := range leyenda {
+		clases = append(clases, c.Clase)
+	}
+	if !reflect.DeepEqual(clases, []string{"subject", "auxiliary_verb", "adjective", "punctuation"}) {
+		t.Errorf("leyendaResultados() = %v", clases)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+	"validar_oraciones/middleware"
+)
+
+// procesarEnParalelo ejecuta trabajo(i) para cada i en [0, total) con como mucho
+// el número de trabajadores indicado (uno por procesador si es 0). Cada trabajo
+// escribe su resultado en la posición i, así que el orden se conserva. Si el
+// contexto se cancela deja de repartir trabajos, espera a los que están en
+// curso y devuelve el error del contexto.
+func procesarEnParalelo(ctx context.Context, total, trabajadores int, trabajo func(i int)) error {
+	if trabajadores <= 0 {
+		trabajadores = runtime.GOMAXPROCS(0)
+	}
+	trabajadores = min(trabajadores, total)
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range trabajadores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				trabajo(i)
+			}
+		}()
+	}
+
+	var err error
+repartir:
+	for i := range total {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break repartir
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// cancelacion describe la respuesta a una validación interrumpida porque el
+// contexto de la petición terminó: 504 si se agotó el plazo y 503 si se
+// canceló. El último resultado es false si no hay que responder porque
+// middleware.Timeout ya escribe el 504.
+func (h *OracionHandler) cancelacion(r *http.Request, err error) (int, string, string, bool) {
+	h.logger.Println("Validation cancelled:", err)
+	if middleware.EnTimeout(r.Context()) {
+		return 0, "", "", false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, ErrorTiempoAgotado, "the validation did not finish in time", true
+	}
+	return http.StatusServiceUnavailable, ErrorCancelada, "the validation was cancelled", true
+}
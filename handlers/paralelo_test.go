@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+func TestProcesarEnParalelo(t *testing.T) {
+	t.Run("ordered and bounded", func(t *testing.T) {
+		var mu sync.Mutex
+		activos, maximo := 0, 0
+		resultados := make([]int, 50)
+		err := procesarEnParalelo(context.Background(), len(resultados), 3, func(i int) {
+			mu.Lock()
+			activos++
+			maximo = max(maximo, activos)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+			resultados[i] = i * i
+
+			mu.Lock()
+			activos--
+			mu.Unlock()
+		})
+		if err != nil {
+			t.Fatalf("procesarEnParalelo() unexpected error = %v", err)
+		}
+		for i, r := range resultados {
+			if r != i*i {
+				t.Fatalf("resultados[%d] = %d, expected %d", i, r, i*i)
+			}
+		}
+		if maximo > 3 {
+			t.Errorf("%d workers ran at once, expected at most 3", maximo)
+		}
+	})
+
+	t.Run("cancellation stops the work", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var hechos atomic.Int32
+		err := procesarEnParalelo(ctx, 1000, 2, func(i int) {
+			if hechos.Add(1) == 10 {
+				cancel()
+			}
+		})
+		if err != context.Canceled {
+			t.Errorf("procesarEnParalelo() error = %v, expected context.Canceled", err)
+		}
+		if n := hechos.Load(); n >= 1000 {
+			t.Errorf("all %d jobs ran after cancellation", n)
+		}
+	})
+
+	cancelaciones := []struct {
+		name   string
+		ruta   string
+		cuerpo string
+		plazo  bool // Cancelar por plazo agotado en lugar de por cancelación
+		estado int
+		codigo string
+	}{
+		{"cancelled batch", "/api/validar/batch", `{"oraciones":["I played football."]}`, false, http.StatusServiceUnavailable, ErrorCancelada},
+		{"batch out of time", "/api/validar/batch", `{"oraciones":["I played football."]}`, true, http.StatusGatewayTimeout, ErrorTiempoAgotado},
+		{"cancelled v1 batch", "/api/v1/validate/batch", `{"texts":["I played football."]}`, false, http.StatusServiceUnavailable, ErrorCancelada},
+		{"v1 batch out of time", "/api/v1/validate/batch", `{"texts":["I played football."]}`, true, http.StatusGatewayTimeout, ErrorTiempoAgotado},
+	}
+	for _, tt := range cancelaciones {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handlerDePrueba(models.NewValidadorConfig())
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/validar/batch", h.HandleAPIValidacionLote)
+			h.RegistrarAPIV1(mux)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.plazo {
+				ctx, cancel = context.WithTimeout(context.Background(), 0)
+			}
+			cancel()
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.ruta, strings.NewReader(tt.cuerpo))
+			mux.ServeHTTP(rec, req.WithContext(ctx))
+			if rec.Code != tt.estado {
+				t.Fatalf("status = %d, expected %d: %s", rec.Code, tt.estado, rec.Body.String())
+			}
+			var respuesta models.RespuestaError
+			if err := json.NewDecoder(rec.Body).Decode(&respuesta); err != nil || respuesta.Error.Codigo != tt.codigo {
+				t.Errorf("body = %+v, %v, expected the %s error code", respuesta, err, tt.codigo)
+			}
+		})
+	}
+}
+
+// oracionesBenchmark devuelve n oraciones variadas, válidas e inválidas
+func oracionesBenchmark(n int) []string {
+	base := []string{
+		"I played football yesterday.",
+		"They was happy at the party.",
+		"She didn't eat the cake last night.",
+		"We goed to the park two days ago.",
+		"The teacher helped the students.",
+		"He is watching a movie now.",
+	}
+	oraciones := make([]string, n)
+	for i := range oraciones {
+		oraciones[i] = base[i%len(base)]
+	}
+	return oraciones
+}
+
+// BenchmarkValidarLote mide un lote de 100 oraciones validadas de una en una y
+// con el pool, que usa un trabajador por procesador. Con -cpu 1,2,4,8 se ve cómo
+// el pool escala con GOMAXPROCS mientras la versión secuencial no cambia.
+func BenchmarkValidarLote(b *testing.B) {
+	oraciones := oracionesBenchmark(100)
+	opciones := []parser.Opcion{parser.ConTodosLosErrores()}
+	validador := parser.ValidadorActual() // Cargar el diccionario fuera de la medición
+
+	h := handlerDePrueba(models.NewValidadorConfig())
+	casos := []struct {
+		name         string
+		trabajadores int
+	}{
+		{"sequential", 1},
+		{"pool", 0},
+	}
+
+	for _, caso := range casos {
+		b.Run(caso.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				procesarEnParalelo(context.Background(), len(oraciones), caso.trabajadores, func(i int) {
+					h.validarOracionLote(validador, oraciones[i], opciones)
+				})
+			}
+			b.ReportMetric(float64(b.N*len(oraciones))/b.Elapsed().Seconds(), "sentences/s")
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"validar_oraciones/models"
+)
+
+// categorias asocia cada tipo de palabra con el nombre que aparece en la
+// leyenda, en el orden de la leyenda; la clase que le da color es el nombre
+// estable del tipo
+var categorias = []struct {
+	tipo   models.TipoPalabra
+	nombre string
+}{
+	{models.TipoSujeto, "Subject"},
+	{models.TipoVerboSimple, "Verb"},
+	{models.TipoVerboEstado, "State verb"},
+	{models.TipoVerboAuxiliar, "Auxiliary verb"},
+	{models.TipoVerboModalPasado, "Modal verb"},
+	{models.TipoNegativo, "Negation"},
+	{models.TipoComplemento, "Complement"},
+	{models.TipoTiempo, "Time expression"},
+	{models.TipoPreposicion, "Preposition"},
+	{models.TipoArticulo, "Article"},
+	{models.TipoAdjetivo, "Adjective"},
+	{models.TipoAdverbio, "Adverb"},
+	{models.TipoConjuncion, "Conjunction"},
+	{models.TipoPronombre, "Pronoun"},
+	{models.TipoInterrogativo, "Question word"},
+	{models.TipoCausaEfecto, "Cause and effect"},
+	{models.TipoRespuestaCorta, "Short answer"},
+	{models.TipoPuntuacion, "Punctuation"},
+	{models.TipoDesconocido, "Unknown word"},
+}
+
+// categoriaToken devuelve la categoría con la que se resalta un tipo de palabra
+func categoriaToken(tipo models.TipoPalabra) models.CategoriaToken {
+	for _, c := range categorias {
+		if c.tipo == tipo {
+			return models.CategoriaToken{Clase: tipo.String(), Nombre: c.nombre}
+		}
+	}
+	return categoriaToken(models.TipoDesconocido)
+}
+
+// fragmentosOracion divide el texto escrito en los tokens que reconoció el
+// análisis léxico y el texto que queda entre ellos. Las posiciones de los
+// tokens y de los diagnósticos deben apuntar a ese mismo texto.
+func fragmentosOracion(texto string, tokens []models.Token, diagnosticos []models.Diagnostic) []models.Fragmento {
+	var fragmentos []models.Fragmento
+	posicion := 0
+
+	for _, token := range tokens {
+		// Las partes de una contracción comparten el texto de la primera
+		if token.ByteInicio < posicion || token.ByteFin > len(texto) || token.ByteInicio >= token.ByteFin {
+			continue
+		}
+		if token.ByteInicio > posicion {
+			fragmentos = append(fragmentos, models.Fragmento{Texto: texto[posicion:token.ByteInicio]})
+		}
+
+		categoria := categoriaToken(token.Tipo)
+		fragmento := models.Fragmento{
+			Texto:     texto[token.ByteInicio:token.ByteFin],
+			Clase:     categoria.Clase,
+			Categoria: categoria.Nombre,
+		}
+		for _, d := range diagnosticos {
+			if senalaToken(d.Rango, token) {
+				fragmento.Errores = append(fragmento.Errores, d.Mensaje)
+			}
+		}
+		fragmentos = append(fragmentos, fragmento)
+		posicion = token.ByteFin
+	}
+
+	if posicion < len(texto) {
+		fragmentos = append(fragmentos, models.Fragmento{Texto: texto[posicion:]})
+	}
+	return fragmentos
+}
+
+// senalaToken indica si el rango de un diagnóstico abarca el token. Un rango
+// vacío marca dónde falta una palabra y señala el token que empieza ahí.
+func senalaToken(rango models.Rango, token models.Token) bool {
+	if rango.ByteInicio == rango.ByteFin {
+		return rango.ByteInicio == token.ByteInicio
+	}
+	return rango.ByteInicio < token.ByteFin && token.ByteInicio < rango.ByteFin
+}
+
+// leyendaResultados reúne las categorías de los tokens que aparecen en los
+// resultados, en el orden de la leyenda
+func leyendaResultados(resultados []models.ResultadoOracion) []models.CategoriaToken {
+	presentes := make(map[string]bool)
+	for _, r := range resultados {
+		for _, f := range r.Fragmentos {
+			presentes[f.Clase] = true
+		}
+	}
+
+	var leyenda []models.CategoriaToken
+	for _, c := range categorias {
+		if categoria := categoriaToken(c.tipo); presentes[categoria.Clase] {
+			leyenda = append(leyenda, categoria)
+		}
+	}
+	return leyenda
+}
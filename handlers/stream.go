@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+// maxLineaStream es la longitud máxima en bytes de una línea del cuerpo
+const maxLineaStream = 64 * 1024
+
+// HandleAPIValidacionStream valida un corpus con una oración por línea y responde
+// con un resultado JSON por línea (NDJSON) a medida que valida cada oración, sin
+// guardar el corpus ni los resultados en memoria. Las líneas vacías se saltan.
+// El perfil y la puntuación estricta llegan en la query (profile,
+// strict_punctuation). Si el cliente se desconecta deja de leer y de validar.
+func (h *OracionHandler) HandleAPIValidacionStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	consulta := r.URL.Query()
+	perfil, err := parser.BuscarPerfil(consulta.Get("profile"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	estricta := false
+	if valor := consulta.Get("strict_punctuation"); valor != "" {
+		if estricta, err = strconv.ParseBool(valor); err != nil {
+			http.Error(w, "strict_punctuation must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+	opciones := h.opcionesValidacion(perfil, estricta)
+
+	// En HTTP/1.x el servidor deja de leer el cuerpo en cuanto se escribe la
+	// respuesta. Con HTTP/2 o en pruebas no hace falta y el error se ignora.
+	rc := http.NewResponseController(w)
+	rc.EnableFullDuplex()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush() // El cliente recibe las cabeceras antes de enviar la primera línea
+
+	lector := bufio.NewScanner(r.Body)
+	lector.Buffer(make([]byte, 0, 4096), maxLineaStream)
+	encoder := json.NewEncoder(w)
+	indice, linea := 0, 0
+	for {
+		h.extenderPlazos(rc)
+		if !lector.Scan() {
+			break
+		}
+		linea++
+		oracion := strings.TrimSpace(lector.Text())
+		if oracion == "" {
+			continue
+		}
+		if err := r.Context().Err(); err != nil {
+			h.logger.Println("Stream validation cancelled:", err)
+			return
+		}
+
+		// Cada línea usa el diccionario en uso al leerla; un stream largo ve las recargas
+		resultado, _ := h.validarOracionLote(parser.ValidadorActual(), oracion, opciones)
+		resultado.Indice, resultado.Linea = indice, linea
+		indice++
+		if err := encoder.Encode(resultado); err != nil {
+			h.logger.Println("Stream validation cancelled:", err)
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			h.logger.Println("Stream validation cancelled:", err)
+			return
+		}
+	}
+
+	if err := lector.Err(); err != nil {
+		if r.Context().Err() != nil {
+			h.logger.Println("Stream validation cancelled:", err)
+			return
+		}
+		mensaje := "error reading the request body"
+		if errors.Is(err, bufio.ErrTooLong) {
+			mensaje = fmt.Sprintf("line is longer than %d bytes", maxLineaStream)
+		}
+		encoder.Encode(models.ResultadoLoteOracion{Indice: indice, Linea: linea + 1, Error: mensaje})
+	}
+}
+
+// extenderPlazos da a la conexión PlazoLinea para leer y escribir la siguiente
+// línea, así un corpus largo no choca con ReadTimeout ni WriteTimeout
+func (h *OracionHandler) extenderPlazos(rc *http.ResponseController) {
+	if h.config.PlazoLinea > 0 {
+		plazo := time.Now().Add(h.config.PlazoLinea)
+		rc.SetReadDeadline(plazo)
+		rc.SetWriteDeadline(plazo)
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"validar_oraciones/middleware"
+	"validar_oraciones/models"
+)
+
+func TestHandleAPIValidacionStream(t *testing.T) {
+	h := handlerDePrueba(models.NewValidadorConfig())
+
+	leerResultados := func(t *testing.T, cuerpo io.Reader) []models.ResultadoLoteOracion {
+		var resultados []models.ResultadoLoteOracion
+		lector := bufio.NewScanner(cuerpo)
+		for lector.Scan() {
+			var resultado models.ResultadoLoteOracion
+			if err := json.Unmarshal(lector.Bytes(), &resultado); err != nil {
+				t.Fatalf("invalid JSON line %q: %v", lector.Text(), err)
+			}
+			resultados = append(resultados, resultado)
+		}
+		return resultados
+	}
+
+	t.Run("one result per line", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		cuerpo := "I played football.\n\n  They was happy.\r\n"
+		h.HandleAPIValidacionStream(rec, httptest.NewRequest(http.MethodPost, "/api/validar/stream", strings.NewReader(cuerpo)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected 200: %s", rec.Code, rec.Body.String())
+		}
+		if tipo := rec.Header().Get("Content-Type"); tipo != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, expected application/x-ndjson", tipo)
+		}
+
+		resultados := leerResultados(t, rec.Body)
+		if len(resultados) != 2 {
+			t.Fatalf("results = %d, expected 2", len(resultados))
+		}
+		esperados := []struct {
+			indice, linea int
+			oracion       string
+			valida        bool
+		}{
+			{0, 1, "I played football.", true},
+			{1, 3, "They was happy.", false},
+		}
+		for i, e := range esperados {
+			r := resultados[i]
+			if r.Indice != e.indice || r.Linea != e.linea || r.Oracion != e.oracion || r.EsValida != e.valida {
+				t.Errorf("results[%d] = {%d %d %q %v}, expected %+v", i, r.Indice, r.Linea, r.Oracion, r.EsValida, e)
+			}
+		}
+	})
+
+	t.Run("line too long", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		cuerpo := "I played football.\n" + strings.Repeat("a", maxLineaStream+1) + "\n"
+		h.HandleAPIValidacionStream(rec, httptest.NewRequest(http.MethodPost, "/api/validar/stream", strings.NewReader(cuerpo)))
+		resultados := leerResultados(t, rec.Body)
+		if len(resultados) != 2 || resultados[1].Error == "" || resultados[1].Linea != 2 {
+			t.Errorf("results = %+v, expected a valid sentence and an error for line 2", resultados)
+		}
+	})
+
+	errores := []struct {
+		name   string
+		metodo string
+		url    string
+		estado int
+	}{
+		{"wrong method", http.MethodGet, "/api/validar/stream", http.StatusMethodNotAllowed},
+		{"unknown profile", http.MethodPost, "/api/validar/stream?profile=future", http.StatusBadRequest},
+		{"invalid strict punctuation", http.MethodPost, "/api/validar/stream?strict_punctuation=maybe", http.StatusBadRequest},
+	}
+	for _, tt := range errores {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.HandleAPIValidacionStream(rec, httptest.NewRequest(tt.metodo, tt.url, strings.NewReader("I played.\n")))
+			if rec.Code != tt.estado {
+				t.Errorf("status = %d, expected %d", rec.Code, tt.estado)
+			}
+		})
+	}
+
+	t.Run("results arrive before the body ends and a disconnect stops the stream", func(t *testing.T) {
+		terminado := make(chan struct{})
+		var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer close(terminado)
+			h.HandleAPIValidacionStream(w, r)
+		})
+		servidor := httptest.NewServer(middleware.LogRequest(handler, log.New(io.Discard, "", 0)))
+		defer servidor.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		lectura, escritura := io.Pipe()
+		defer escritura.Close()
+		context.AfterFunc(ctx, func() { escritura.CloseWithError(ctx.Err()) })
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, servidor.URL, lectura)
+		resp, err := servidor.Client().Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		respuesta := bufio.NewReader(resp.Body)
+		for _, oracion := range []string{"I played football.", "They was happy."} {
+			io.WriteString(escritura, oracion+"\n")
+			linea, err := respuesta.ReadBytes('\n')
+			if err != nil {
+				t.Fatalf("no result for %q while the body is still open: %v", oracion, err)
+			}
+			var resultado models.ResultadoLoteOracion
+			if err := json.Unmarshal(linea, &resultado); err != nil || resultado.Oracion != oracion {
+				t.Fatalf("result = %s (%v), expected %q", linea, err, oracion)
+			}
+		}
+
+		cancel()
+		select {
+		case <-terminado:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the handler kept running after the client disconnected")
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
+)
+
+const (
+	// maxTextoVivo es el tamaño máximo en bytes del cuerpo de POST /api/live
+	maxTextoVivo = 16 * 1024
+	// latidoVivo es cada cuánto se envía un comentario para que los proxies no
+	// cierren una conexión SSE sin eventos
+	latidoVivo = 15 * time.Second
+)
+
+// sesionVivo guarda el último texto recibido en una sesión de validación en
+// vivo; los textos intermedios se descartan
+type sesionVivo struct {
+	mu       sync.Mutex
+	peticion models.PeticionVivo
+	cambios  chan struct{} // Capacidad 1: avisa de que hay un texto nuevo
+}
+
+// actualizar guarda la petición si es más reciente que la anterior y avisa a
+// la conexión SSE de la sesión
+func (s *sesionVivo) actualizar(peticion models.PeticionVivo) {
+	s.mu.Lock()
+	if peticion.Version >= s.peticion.Version {
+		s.peticion = peticion
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.cambios <- struct{}{}:
+	default: // Ya hay un aviso pendiente
+	}
+}
+
+// ultima devuelve el último texto recibido
+func (s *sesionVivo) ultima() models.PeticionVivo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peticion
+}
+
+// HandleAPIVivo valida en vivo el texto que escribe el estudiante. GET abre un
+// stream SSE que empieza con un evento "session" con el identificador de la
+// sesión; POST envía a esa sesión el texto actual y, cuando el texto deja de
+// cambiar durante EsperaVivo, el stream recibe un evento "result" con los
+// tokens resaltados y los diagnósticos de cada oración.
+func (h *OracionHandler) HandleAPIVivo(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleVivoEventos(w, r)
+	case http.MethodPost:
+		h.handleVivoTexto(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleVivoEventos mantiene abierta la sesión y envía un resultado por cada
+// pausa en la escritura
+func (h *OracionHandler) handleVivoEventos(w http.ResponseWriter, r *http.Request) {
+	id, err := nuevoIDSesion()
+	if err != nil {
+		h.logger.Println("Error creating the live session:", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	sesion := &sesionVivo{cambios: make(chan struct{}, 1)}
+	h.sesiones.Store(id, sesion)
+	defer h.sesiones.Delete(id)
+
+	// La conexión dura lo que quiera el cliente: sin plazo de lectura (la
+	// desconexión se detecta leyendo) y con un plazo por cada escritura
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	enviar := func(evento string, datos any) bool {
+		contenido, err := json.Marshal(datos)
+		if err != nil {
+			h.logger.Printf("Error encoding the %s event: %v", evento, err)
+			return false
+		}
+		return h.escribirEvento(rc, w, fmt.Sprintf("event: %s\ndata: %s\n\n", evento, contenido))
+	}
+	if !enviar("session", map[string]string{"sesion": id}) {
+		return
+	}
+
+	espera := time.NewTimer(h.config.EsperaVivo)
+	espera.Stop()
+	latido := time.NewTicker(latidoVivo)
+	defer latido.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sesion.cambios:
+			espera.Reset(h.config.EsperaVivo)
+		case <-espera.C:
+			resultado, err := h.validarVivo(r.Context(), sesion.ultima())
+			if err != nil {
+				return
+			}
+			if !enviar("result", resultado) {
+				return
+			}
+		case <-latido.C:
+			if !h.escribirEvento(rc, w, ": ping\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// escribirEvento escribe y envía un evento SSE; devuelve false si el cliente ya
+// no lo puede recibir
+func (h *OracionHandler) escribirEvento(rc *http.ResponseController, w http.ResponseWriter, evento string) bool {
+	if h.config.PlazoLinea > 0 {
+		rc.SetWriteDeadline(time.Now().Add(h.config.PlazoLinea))
+	}
+	if _, err := fmt.Fprint(w, evento); err != nil {
+		h.logger.Println("Live validation closed:", err)
+		return false
+	}
+	if err := rc.Flush(); err != nil {
+		h.logger.Println("Live validation closed:", err)
+		return false
+	}
+	return true
+}
+
+// handleVivoTexto recibe el texto actual de una sesión
+func (h *OracionHandler) handleVivoTexto(w http.ResponseWriter, r *http.Request) {
+	var peticion models.PeticionVivo
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTextoVivo)).Decode(&peticion); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if _, err := parser.BuscarPerfil(peticion.Perfil); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sesion, ok := h.sesiones.Load(peticion.Sesion)
+	if !ok {
+		http.Error(w, "Unknown live session", http.StatusNotFound)
+		return
+	}
+	sesion.(*sesionVivo).actualizar(peticion)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// validarVivo valida el texto de una sesión con las mismas reglas y límites que
+// el formulario
+func (h *OracionHandler) validarVivo(ctx context.Context, peticion models.PeticionVivo) (models.ResultadoVivo, error) {
+	resultado := models.ResultadoVivo{Version: peticion.Version, Oraciones: []models.OracionVivo{}}
+
+	segmentos := h.procesarEntrada(peticion.Texto)
+	if len(segmentos) > h.config.MaxOraciones {
+		resultado.Error = fmt.Sprintf("Please enter a maximum of %d sentences.", h.config.MaxOraciones)
+		return resultado, nil
+	}
+
+	// El perfil ya se comprobó al recibir el texto
+	perfil, _ := parser.BuscarPerfil(peticion.Perfil)
+	oraciones, err := h.validarOraciones(ctx, segmentos, h.opcionesValidacion(perfil, peticion.PuntuacionEstricta))
+	if err != nil {
+		return resultado, err
+	}
+
+	for _, o := range oraciones {
+		resultado.Oraciones = append(resultado.Oraciones, models.OracionVivo{
+			Segmento:     o.Segmento,
+			EsValida:     o.EsValida,
+			Mensaje:      o.Mensaje,
+			Explicacion:  o.Explicacion,
+			Correccion:   o.Correccion,
+			Diagnosticos: o.Diagnosticos,
+			Fragmentos:   o.Fragmentos,
+		})
+	}
+	return resultado, nil
+}
+
+// nuevoIDSesion genera un identificador de sesión difícil de adivinar
+func nuevoIDSesion() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
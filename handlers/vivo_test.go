@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"validar_oraciones/models"
+)
+
+// leerEventoSSE lee el siguiente evento de un stream SSE y devuelve su nombre y
+// sus datos; los comentarios se saltan
+func leerEventoSSE(t *testing.T, lector *bufio.Reader) (string, string) {
+	t.Helper()
+	var evento, datos string
+	for {
+		linea, err := lector.ReadString('\n')
+		if err != nil {
+			t.Fatalf("the event stream ended: %v", err)
+		}
+		linea = strings.TrimRight(linea, "\n")
+		switch {
+		case linea == "" && evento != "":
+			return evento, datos
+		case strings.HasPrefix(linea, "event: "):
+			evento = strings.TrimPrefix(linea, "event: ")
+		case strings.HasPrefix(linea, "data: "):
+			datos = strings.TrimPrefix(linea, "data: ")
+		}
+	}
+}
+
+func TestHandleAPIVivo(t *testing.T) {
+	config := models.NewValidadorConfig()
+	config.EsperaVivo = 100 * time.Millisecond
+	h := handlerDePrueba(config)
+	servidor := httptest.NewServer(http.HandlerFunc(h.HandleAPIVivo))
+	defer servidor.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, servidor.URL, nil)
+	resp, err := servidor.Client().Do(req)
+	if err != nil {
+		t.Fatalf("opening the event stream failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if tipo := resp.Header.Get("Content-Type"); tipo != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, expected text/event-stream", tipo)
+	}
+
+	eventos := bufio.NewReader(resp.Body)
+	evento, datos := leerEventoSSE(t, eventos)
+	var sesion struct{ Sesion string }
+	if err := json.Unmarshal([]byte(datos), &sesion); evento != "session" || err != nil || sesion.Sesion == "" {
+		t.Fatalf("first event = %s %s, expected the session", evento, datos)
+	}
+
+	enviar := func(cuerpo string) int {
+		resp, err := http.Post(servidor.URL, "application/json", strings.NewReader(cuerpo))
+		if err != nil {
+			t.Fatalf("sending the text failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	texto := func(version int, texto string) string {
+		return fmt.Sprintf(`{"sesion":%q,"version":%d,"texto":%q}`, sesion.Sesion, version, texto)
+	}
+
+	t.Run("only the last text of a burst is validated", func(t *testing.T) {
+		for i, parcial := range []string{"They", "They was", "They was happy."} {
+			if estado := enviar(texto(i+1, parcial)); estado != http.StatusAccepted {
+				t.Fatalf("status = %d, expected 202", estado)
+			}
+		}
+
+		evento, datos := leerEventoSSE(t, eventos)
+		var resultado models.ResultadoVivo
+		if err := json.Unmarshal([]byte(datos), &resultado); evento != "result" || err != nil {
+			t.Fatalf("event = %s %s (%v), expected a result", evento, datos, err)
+		}
+		if resultado.Version != 3 || len(resultado.Oraciones) != 1 {
+			t.Fatalf("result = %+v, expected version 3 with one sentence", resultado)
+		}
+		oracion := resultado.Oraciones[0]
+		if oracion.EsValida || len(oracion.Diagnosticos) == 0 || len(oracion.Fragmentos) == 0 {
+			t.Errorf("sentence = %+v, expected an invalid sentence with diagnostics and fragments", oracion)
+		}
+	})
+
+	t.Run("too many sentences", func(t *testing.T) {
+		enviar(texto(4, strings.Repeat("I played. ", config.MaxOraciones+1)))
+		_, datos := leerEventoSSE(t, eventos)
+		var resultado models.ResultadoVivo
+		if err := json.Unmarshal([]byte(datos), &resultado); err != nil || resultado.Error == "" {
+			t.Errorf("result = %s, expected an error", datos)
+		}
+	})
+
+	errores := []struct {
+		name   string
+		cuerpo string
+		estado int
+	}{
+		{"invalid JSON", `{"sesion":`, http.StatusBadRequest},
+		{"unknown profile", `{"sesion":"` + sesion.Sesion + `","profile":"future"}`, http.StatusBadRequest},
+		{"unknown session", `{"sesion":"nope","texto":"I played."}`, http.StatusNotFound},
+	}
+	for _, tt := range errores {
+		t.Run(tt.name, func(t *testing.T) {
+			if estado := enviar(tt.cuerpo); estado != tt.estado {
+				t.Errorf("status = %d, expected %d", estado, tt.estado)
+			}
+		})
+	}
+
+	t.Run("closing the stream ends the session", func(t *testing.T) {
+		cancel()
+		for range 50 {
+			if _, ok := h.sesiones.Load(sesion.Sesion); !ok {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Error("the session is still open after the client disconnected")
+	})
+}
@@ -1,28 +1,38 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 	"validar_oraciones/handlers"
 	"validar_oraciones/middleware"
 	"validar_oraciones/models"
+	parser "validar_oraciones/parser"
 )
 
 // NewConfig crea una nueva configuración con valores por defecto
 func NewConfig() *models.Config {
 	return &models.Config{
 		Port:            "8080",
-		StaticDir:       "static",
-		TemplatesDir:    "templates",
+		StaticDir:       os.Getenv("STATIC_DIR"),
+		TemplatesDir:    os.Getenv("TEMPLATES_DIR"),
 		MaxRequestSize:  1 << 20, // 1 MB
 		ReadTimeout:     5 * time.Second,
 		WriteTimeout:    10 * time.Second,
 		RequestTimeout:  30 * time.Second,
 		MaxOraciones:    5,
+		MaxLote:         100,
 		EnableCORS:      true,
 		EnableRateLimit: true,
+
+		RutaDiccionario:      os.Getenv("DICTIONARY_PATH"),
+		IntervaloDiccionario: 5 * time.Second,
+		AdminToken:           os.Getenv("ADMIN_TOKEN"),
 	}
 }
 
@@ -33,14 +43,42 @@ func main() {
 	// Cargar configuración
 	config := NewConfig()
 
+	// La administración edita el archivo del diccionario; el incluido en el
+	// binario es de solo lectura y todas las ediciones fallarían
+	if config.AdminToken != "" && config.RutaDiccionario == "" {
+		logger.Fatal("ADMIN_TOKEN requiere DICTIONARY_PATH: el diccionario incluido no se puede editar")
+	}
+
+	// Cargar el diccionario antes de aceptar peticiones: el archivo configurado
+	// o, si no hay ninguno, el incluido en el binario
+	if config.RutaDiccionario != "" {
+		if err := parser.CargarDiccionario(config.RutaDiccionario); err != nil {
+			logger.Fatal("Error al cargar el diccionario:", err)
+		}
+		logger.Printf("Diccionario cargado desde %s", config.RutaDiccionario)
+	} else if err := parser.CargarDiccionarioFS(recursos, archivoDiccionario); err != nil {
+		logger.Fatal("Error al cargar el diccionario incluido:", err)
+	}
+
+	plantillas, err := directorio(config.TemplatesDir, "templates")
+	if err != nil {
+		logger.Fatal("Error al abrir las plantillas:", err)
+	}
+	estaticos, err := directorio(config.StaticDir, "static")
+	if err != nil {
+		logger.Fatal("Error al abrir los archivos estáticos:", err)
+	}
+
 	// Crear router
 	mux := http.NewServeMux()
 
 	// Configurar el handler de oraciones
 	validadorConfig := models.NewValidadorConfig()
 	validadorConfig.MaxOraciones = config.MaxOraciones
+	validadorConfig.MaxLote = config.MaxLote
+	validadorConfig.PlazoLinea = config.WriteTimeout
 
-	oracionHandler, err := handlers.NewOracionHandler(validadorConfig, logger)
+	oracionHandler, err := handlers.NewOracionHandler(validadorConfig, plantillas, logger)
 	if err != nil {
 		logger.Fatal("Error al crear el handler de oraciones:", err)
 	}
@@ -54,27 +92,66 @@ func main() {
 	// Agregar middleware de recuperación de pánico
 	handler = middleware.RecoverPanic(handler, logger)
 
-	// Agregar middleware de timeout
-	handler = middleware.Timeout(handler, config.RequestTimeout)
+	// Agregar middleware de timeout; la validación en streaming y en vivo puede durar más
+	handler = middleware.Timeout(handler, config.RequestTimeout, "/api/validar/stream", "/api/live")
 
 	// Agregar middleware de CORS si está habilitado
 	if config.EnableCORS {
 		handler = middleware.CORS(handler)
 	}
 
-	// Agregar middleware de rate limiting si está habilitado
+	// Agregar middleware de rate limiting si está habilitado; los POST de la
+	// validación en vivo llegan con cada pausa al escribir y tienen un cupo mayor
 	if config.EnableRateLimit {
-		handler = middleware.RateLimit(handler, 100) // 100 requests per minute
+		handler = middleware.RateLimit(handler, 100, // 100 requests per minute per client
+			middleware.LimiteRuta{Ruta: "POST /api/live", PorMinuto: 600})
 	}
 
 	// Configurar rutas estáticas
-	fsHandler := http.FileServer(http.Dir(config.StaticDir))
+	fsHandler := http.FileServer(http.FS(estaticos))
 	mux.Handle("/static/", http.StripPrefix("/static/", cacheControl(fsHandler)))
 
 	// Configurar rutas de la API
 	mux.Handle("/", oracionHandler)
 	mux.HandleFunc("/api/validar", oracionHandler.HandleAPIValidation)
+	mux.HandleFunc("/api/validar/batch", oracionHandler.HandleAPIValidacionLote)
+	mux.HandleFunc("/api/validar/stream", oracionHandler.HandleAPIValidacionStream)
+	mux.HandleFunc("/api/live", oracionHandler.HandleAPIVivo)
+	mux.HandleFunc("/api/types", oracionHandler.HandleAPITipos)
 	mux.HandleFunc("/api/health", handleHealth)
+	mux.HandleFunc("/api/ready", handleReady)
+
+	// API versionada, descrita en /api/v1/openapi.json
+	oracionHandler.RegistrarAPIV1(mux)
+
+	// Administración, solo si hay un token configurado
+	if config.AdminToken != "" {
+		admin := http.NewServeMux()
+		oracionHandler.RegistrarAdmin(admin)
+		mux.Handle("/api/admin/", middleware.RequireToken(admin, config.AdminToken))
+	} else {
+		logger.Println("API de administración desactivada: ADMIN_TOKEN no está definido")
+	}
+
+	// Recargar el diccionario cuando cambie el archivo configurado o al recibir
+	// SIGHUP; el incluido en el binario no cambia y no se vigila
+	informarRecarga := func(err error) {
+		if err != nil {
+			logger.Println("Error al recargar el diccionario, se mantiene el anterior:", err)
+			return
+		}
+		logger.Printf("Diccionario recargado: %d entradas", parser.EntradasDiccionario())
+	}
+	if config.RutaDiccionario != "" && config.IntervaloDiccionario > 0 {
+		go parser.VigilarDiccionario(context.Background(), config.IntervaloDiccionario, informarRecarga)
+	}
+	senales := make(chan os.Signal, 1)
+	signal.Notify(senales, syscall.SIGHUP)
+	go func() {
+		for range senales {
+			informarRecarga(parser.RecargarDiccionario())
+		}
+	}()
 
 	// Configurar el servidor
 	server := &http.Server{
@@ -106,3 +183,20 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write([]byte(`{"status":"OK"}`))
 }
+
+// handleReady indica si el servidor puede validar oraciones: responde 503
+// mientras no haya un diccionario cargado
+func handleReady(w http.ResponseWriter, r *http.Request) {
+	estado := parser.EstadoDiccionario()
+	respuesta := struct {
+		Status      string                   `json:"status"`
+		Diccionario models.EstadoDiccionario `json:"diccionario"`
+	}{Status: "ready", Diccionario: estado}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !estado.Listo {
+		respuesta.Status = "unavailable"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(respuesta)
+}
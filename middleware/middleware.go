@@ -2,9 +2,13 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"log"
+	"net"
 	"net/http"
 	"runtime/debug"
+	"slices"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -40,12 +44,28 @@ func RecoverPanic(next http.Handler, logger *log.Logger) http.Handler {
 	})
 }
 
-// Timeout agrega un timeout al contexto de la petición
-func Timeout(next http.Handler, timeout time.Duration) http.Handler {
+// claveTimeout marca en el contexto las peticiones que atiende Timeout
+type claveTimeout struct{}
+
+// EnTimeout indica si la petición pasa por Timeout, que responde 504 cuando su
+// contexto termina; el handler no debe escribir otra respuesta en ese caso
+func EnTimeout(ctx context.Context) bool {
+	marcada, _ := ctx.Value(claveTimeout{}).(bool)
+	return marcada
+}
+
+// Timeout agrega un timeout al contexto de la petición. Las rutas exentas son
+// las que transmiten la respuesta poco a poco; no tienen un plazo total y cada
+// handler controla el plazo de cada escritura.
+func Timeout(next http.Handler, timeout time.Duration, exentas ...string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(exentas, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		ctx, cancel := context.WithTimeout(r.Context(), timeout)
 		defer cancel()
-		r = r.WithContext(ctx)
+		r = r.WithContext(context.WithValue(ctx, claveTimeout{}, true))
 		done := make(chan bool)
 		go func() {
 			next.ServeHTTP(w, r)
@@ -77,11 +97,91 @@ func CORS(next http.Handler) http.Handler {
 	})
 }
 
-// RateLimit implementa rate limiting
-func RateLimit(next http.Handler, requestsPerMinute float64) http.Handler {
-	limiter := rate.NewLimiter(rate.Limit(requestsPerMinute/60), int(requestsPerMinute))
+// Limpieza de los limitadores por cliente
+const (
+	// clienteInactivo es cuánto tiempo sin peticiones se conserva el limitador
+	// de un cliente; después de un minuto ya tiene la ráfaga completa
+	clienteInactivo = 3 * time.Minute
+	// limpiezaClientes es cada cuánto se buscan clientes inactivos
+	limpiezaClientes = time.Minute
+)
+
+// LimiteRuta da a una ruta, indicada como "MÉTODO /ruta", su propio límite de
+// peticiones por minuto en lugar del general
+type LimiteRuta struct {
+	Ruta      string
+	PorMinuto float64
+}
+
+// clienteLimitado es el limitador de un cliente y su última petición
+type clienteLimitado struct {
+	limiter *rate.Limiter
+	visto   time.Time
+}
+
+// limitadorClientes guarda un limitador por IP con el mismo cupo para todas;
+// los de clientes inactivos se descartan
+type limitadorClientes struct {
+	porMinuto float64
+	ahora     func() time.Time
+
+	mu       sync.Mutex
+	clientes map[string]*clienteLimitado
+	limpieza time.Time // Última búsqueda de clientes inactivos
+}
+
+// nuevoLimitadorClientes crea un limitador por IP con el cupo indicado
+func nuevoLimitadorClientes(porMinuto float64, ahora func() time.Time) *limitadorClientes {
+	return &limitadorClientes{
+		porMinuto: porMinuto,
+		ahora:     ahora,
+		clientes:  make(map[string]*clienteLimitado),
+		limpieza:  ahora(),
+	}
+}
+
+// permitir consume una petición del cupo del cliente
+func (l *limitadorClientes) permitir(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ahora := l.ahora()
+	if ahora.Sub(l.limpieza) > limpiezaClientes {
+		for clave, cliente := range l.clientes {
+			if ahora.Sub(cliente.visto) > clienteInactivo {
+				delete(l.clientes, clave)
+			}
+		}
+		l.limpieza = ahora
+	}
+
+	cliente, existe := l.clientes[ip]
+	if !existe {
+		cliente = &clienteLimitado{limiter: rate.NewLimiter(rate.Limit(l.porMinuto/60), int(l.porMinuto))}
+		l.clientes[ip] = cliente
+	}
+	cliente.visto = ahora
+	return cliente.limiter.AllowN(ahora, 1)
+}
+
+// RateLimit limita las peticiones por minuto de cada cliente, identificado por
+// su dirección IP; un cliente que agota su cupo no afecta a los demás. Detrás
+// de un proxy todos los clientes comparten su IP. Las rutas con límite propio
+// (por ejemplo, los POST de /api/live, que llegan mientras el usuario escribe)
+// tienen un cupo por IP aparte que no consume el general.
+func RateLimit(next http.Handler, requestsPerMinute float64, propios ...LimiteRuta) http.Handler {
+	general := nuevoLimitadorClientes(requestsPerMinute, time.Now)
+	porRuta := make(map[string]*limitadorClientes, len(propios))
+	for _, limite := range propios {
+		porRuta[limite.Ruta] = nuevoLimitadorClientes(limite.PorMinuto, time.Now)
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !limiter.Allow() {
+		limitador, existe := porRuta[r.Method+" "+r.URL.Path]
+		if !existe {
+			limitador = general
+		}
+		if !limitador.permitir(ipCliente(r)) {
 			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
 			return
 		}
@@ -89,6 +189,29 @@ func RateLimit(next http.Handler, requestsPerMinute float64) http.Handler {
 	})
 }
 
+// ipCliente devuelve la IP de la conexión sin el puerto
+func ipCliente(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// RequireToken solo deja pasar las peticiones que envían el token en la
+// cabecera Authorization ("Bearer <token>")
+func RequireToken(next http.Handler, token string) http.Handler {
+	esperado := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), esperado) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // statusWriter wraps http.ResponseWriter to capture status code
 type statusWriter struct {
 	http.ResponseWriter
@@ -106,3 +229,9 @@ func (w *statusWriter) Write(b []byte) (int, error) {
 	}
 	return w.ResponseWriter.Write(b)
 }
+
+// Unwrap permite a http.ResponseController llegar al writer original para
+// vaciar el buffer o cambiar los plazos de la conexión
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
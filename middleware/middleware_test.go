@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	handler := RateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), 2,
+		LimiteRuta{Ruta: "POST /api/live", PorMinuto: 3})
+
+	// Los pasos comparten los cupos, así que se ejecutan en orden
+	pasos := []struct {
+		name   string
+		metodo string
+		ruta   string
+		ip     string
+		estado int
+	}{
+		{"first request", http.MethodGet, "/", "192.0.2.1", http.StatusOK},
+		{"second request", http.MethodPost, "/api/validar", "192.0.2.1", http.StatusOK},
+		{"over the limit", http.MethodGet, "/", "192.0.2.1", http.StatusTooManyRequests},
+		{"another client", http.MethodGet, "/", "192.0.2.2", http.StatusOK},
+		{"own limit is separate", http.MethodPost, "/api/live", "192.0.2.1", http.StatusOK},
+		{"own limit second", http.MethodPost, "/api/live", "192.0.2.1", http.StatusOK},
+		{"own limit third", http.MethodPost, "/api/live", "192.0.2.1", http.StatusOK},
+		{"own limit exhausted", http.MethodPost, "/api/live", "192.0.2.1", http.StatusTooManyRequests},
+		{"own limit for another client", http.MethodPost, "/api/live", "192.0.2.2", http.StatusOK},
+		{"other methods use the general limit", http.MethodGet, "/api/live", "192.0.2.1", http.StatusTooManyRequests},
+	}
+	for _, tt := range pasos {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.metodo, tt.ruta, nil)
+			req.RemoteAddr = tt.ip + ":1234"
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.estado {
+				t.Fatalf("status = %d, expected %d", rec.Code, tt.estado)
+			}
+			if tt.estado == http.StatusTooManyRequests && !strings.Contains(rec.Body.String(), "Too Many Requests") {
+				t.Errorf("body = %q, expected Too Many Requests", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestLimitadorClientes(t *testing.T) {
+	ahora := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := nuevoLimitadorClientes(60, func() time.Time { return ahora })
+
+	for range 60 {
+		l.permitir("192.0.2.1")
+	}
+	if l.permitir("192.0.2.1") {
+		t.Error("permitir() allowed a request over the burst")
+	}
+	ahora = ahora.Add(2 * time.Second)
+	if !l.permitir("192.0.2.1") {
+		t.Error("permitir() did not refill the quota over time")
+	}
+
+	// Sin peticiones durante clienteInactivo el limitador se descarta
+	ahora = ahora.Add(2 * time.Minute)
+	l.permitir("192.0.2.2")
+	if len(l.clientes) != 2 {
+		t.Fatalf("clients = %d, expected 2 before the inactivity period", len(l.clientes))
+	}
+	ahora = ahora.Add(2 * time.Minute)
+	l.permitir("192.0.2.2")
+	var ips []string
+	for ip := range l.clientes {
+		ips = append(ips, ip)
+	}
+	if !slices.Equal(ips, []string{"192.0.2.2"}) {
+		t.Errorf("clients = %v, expected only the active one", ips)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name      string
+		ruta      string
+		estado    int
+		enTimeout bool
+	}{
+		{"slow request", "/api/validar", http.StatusGatewayTimeout, true},
+		{"exempt route", "/api/live", http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marcada := make(chan bool, 1)
+			handler := Timeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				marcada <- EnTimeout(r.Context())
+				select {
+				case <-r.Context().Done():
+					// Sin escribir: la respuesta es el 504 del middleware
+				case <-time.After(50 * time.Millisecond):
+					w.WriteHeader(http.StatusOK)
+				}
+			}), 10*time.Millisecond, "/api/live")
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.ruta, nil))
+			if rec.Code != tt.estado {
+				t.Errorf("status = %d, expected %d", rec.Code, tt.estado)
+			}
+			if got := <-marcada; got != tt.enTimeout {
+				t.Errorf("EnTimeout() = %v, expected %v", got, tt.enTimeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+// Tipos de la API versionada (/api/v1). Sus nombres JSON están en inglés y
+// forman parte del contrato publicado en openapi.json: no deben cambiar.
+
+// PeticionValidacion es el cuerpo de POST /api/v1/validate
+type PeticionValidacion struct {
+	Texto              string `json:"text"`
+	Perfil             string `json:"profile,omitempty"`
+	PuntuacionEstricta bool   `json:"strict_punctuation,omitempty"`
+}
+
+// RespuestaValidacion es el resultado de validar un texto
+type RespuestaValidacion struct {
+	Texto        string           `json:"text"`
+	Perfil       string           `json:"profile"`
+	Valida       bool             `json:"valid"`
+	Mensaje      string           `json:"message"`
+	Explicacion  string           `json:"explanation"`
+	Correccion   string           `json:"correction,omitempty"`
+	Oraciones    []OracionAPI     `json:"sentences"`
+	Tokens       []TokenAPI       `json:"tokens"`
+	Diagnosticos []DiagnosticoAPI `json:"diagnostics"`
+}
+
+// PeticionTokenizar es el cuerpo de POST /api/v1/tokenize
+type PeticionTokenizar struct {
+	Texto string `json:"text"`
+}
+
+// RespuestaTokenizar contiene los tokens de un texto sin validarlo
+type RespuestaTokenizar struct {
+	Texto     string       `json:"text"`
+	Oraciones []OracionAPI `json:"sentences"`
+	Tokens    []TokenAPI   `json:"tokens"`
+}
+
+// PeticionLote es el cuerpo de POST /api/v1/validate/batch
+type PeticionLote struct {
+	Textos             []string `json:"texts"`
+	Perfil             string   `json:"profile,omitempty"`
+	PuntuacionEstricta bool     `json:"strict_punctuation,omitempty"`
+}
+
+// RespuestaLote contiene un resultado por cada texto, en el orden recibido
+type RespuestaLote struct {
+	Resultados []ResultadoLote `json:"results"`
+}
+
+// ResultadoLote es el resultado de un texto del lote: su validación o el error
+// que impidió validarlo
+type ResultadoLote struct {
+	Indice    int                  `json:"index"`
+	Resultado *RespuestaValidacion `json:"result,omitempty"`
+	Error     *ErrorAPI            `json:"error,omitempty"`
+}
+
+// RespuestaDiccionario describe una palabra del diccionario
+type RespuestaDiccionario struct {
+	Palabra    string      `json:"word"`
+	Encontrada bool        `json:"found"`
+	Tipo       TipoPalabra `json:"type"`
+	Lema       string      `json:"lemma,omitempty"`
+	Forma      FormaVerbal `json:"form,omitempty"`
+	SubTipo    string      `json:"subtype,omitempty"`
+}
+
+// OracionAPI ubica una oración dentro del texto recibido (bytes, fin exclusivo)
+type OracionAPI struct {
+	Texto  string `json:"text"`
+	Inicio int    `json:"start"`
+	Fin    int    `json:"end"`
+}
+
+// TokenAPI es un token del análisis léxico; las posiciones apuntan al texto
+// recibido. Las partes de una contracción tras la primera ("not" en "wasn't")
+// llevan su texto normalizado y comparten la posición de la contracción.
+type TokenAPI struct {
+	Texto       string      `json:"text"`
+	Normalizado string      `json:"normalized"`
+	Tipo        TipoPalabra `json:"type"`
+	Lema        string      `json:"lemma,omitempty"`
+	Forma       FormaVerbal `json:"form,omitempty"`
+	ByteInicio  int         `json:"byte_start"`
+	ByteFin     int         `json:"byte_end"`
+	RunaInicio  int         `json:"rune_start"`
+	RunaFin     int         `json:"rune_end"`
+}
+
+// DiagnosticoAPI describe un problema encontrado en el texto
+type DiagnosticoAPI struct {
+	Regla      string    `json:"rule"`
+	Severidad  Severidad `json:"severity"`
+	Mensaje    string    `json:"message"`
+	Rango      RangoAPI  `json:"span"`
+	Sugerencia string    `json:"suggestion,omitempty"`
+	Correccion string    `json:"correction,omitempty"`
+}
+
+// RangoAPI delimita un fragmento del texto en palabras, bytes y runas (fin exclusivo)
+type RangoAPI struct {
+	PalabraInicio int `json:"word_start"`
+	PalabraFin    int `json:"word_end"`
+	ByteInicio    int `json:"byte_start"`
+	ByteFin       int `json:"byte_end"`
+	RunaInicio    int `json:"rune_start"`
+	RunaFin       int `json:"rune_end"`
+}
+
+// RespuestaError es el cuerpo de todas las respuestas de error de la API
+type RespuestaError struct {
+	Error ErrorAPI `json:"error"`
+}
+
+// ErrorAPI identifica un error con un código estable y un mensaje legible
+type ErrorAPI struct {
+	Codigo  string `json:"code"`
+	Mensaje string `json:"message"`
+}
+
+// NuevoTokenAPI convierte un token del análisis léxico al formato de la API
+func NuevoTokenAPI(token Token) TokenAPI {
+	texto := token.Original
+	if texto == "" {
+		texto = token.Texto
+	}
+	return TokenAPI{
+		Texto:       texto,
+		Normalizado: token.Texto,
+		Tipo:        token.Tipo,
+		Lema:        token.Metadata.Lema,
+		Forma:       token.Metadata.Forma,
+		ByteInicio:  token.ByteInicio,
+		ByteFin:     token.ByteFin,
+		RunaInicio:  token.RunaInicio,
+		RunaFin:     token.RunaFin,
+	}
+}
+
+// NuevoDiagnosticoAPI convierte un diagnóstico al formato de la API
+func NuevoDiagnosticoAPI(d Diagnostic) DiagnosticoAPI {
+	return DiagnosticoAPI{
+		Regla:     d.Regla,
+		Severidad: d.Severidad,
+		Mensaje:   d.Mensaje,
+		Rango: RangoAPI{
+			PalabraInicio: d.Rango.PalabraInicio,
+			PalabraFin:    d.Rango.PalabraFin,
+			ByteInicio:    d.Rango.ByteInicio,
+			ByteFin:       d.Rango.ByteFin,
+			RunaInicio:    d.Rango.RunaInicio,
+			RunaFin:       d.Rango.RunaFin,
+		},
+		Sugerencia: d.Sugerencia,
+		Correccion: d.Correccion,
+	}
+}
@@ -18,11 +18,15 @@ func TestNewConfig(t *testing.T) {
 
 	// Comparación de otros valores
 	assert.Equal(t, "8080", config.Port, "El puerto debe ser 8080")
-	assert.Equal(t, "static", config.StaticDir, "El directorio estático debe ser 'static'")
-	assert.Equal(t, "templates", config.TemplatesDir, "El directorio de plantillas debe ser 'templates'")
+	assert.Empty(t, config.StaticDir, "Los archivos estáticos deben ser los incluidos en el binario")
+	assert.Empty(t, config.TemplatesDir, "Las plantillas deben ser las incluidas en el binario")
 	assert.Equal(t, int64(1<<20), config.MaxRequestSize, "El tamaño máximo de la solicitud debe ser 1 MB")
+	assert.Equal(t, 100, config.MaxLote, "El lote máximo debe ser de 100 oraciones")
 	assert.Equal(t, true, config.EnableCORS, "CORS debe estar habilitado")
 	assert.Equal(t, true, config.EnableRateLimit, "El límite de tasa debe estar habilitado")
+	assert.Empty(t, config.RutaDiccionario, "El diccionario debe ser el incluido en el binario")
+	assert.Equal(t, 5*time.Second, config.IntervaloDiccionario, "El diccionario debe comprobarse cada 5 segundos")
+	assert.Empty(t, config.AdminToken, "Los endpoints de administración deben estar desactivados por defecto")
 }
 
 func TestNewValidadorConfig(t *testing.T) {
@@ -39,9 +43,27 @@ func TestNewValidadorConfig(t *testing.T) {
 	if validadorConfig.MaxOraciones != 5 {
 		t.Errorf("Expected MaxOraciones 5, but got %d", validadorConfig.MaxOraciones)
 	}
+	if validadorConfig.MaxLote != 100 {
+		t.Errorf("Expected MaxLote 100, but got %d", validadorConfig.MaxLote)
+	}
+	if validadorConfig.Trabajadores != 0 {
+		t.Errorf("Expected Trabajadores 0, but got %d", validadorConfig.Trabajadores)
+	}
+	if validadorConfig.PlazoLinea != 10*time.Second {
+		t.Errorf("Expected PlazoLinea 10s, but got %v", validadorConfig.PlazoLinea)
+	}
+	if validadorConfig.EsperaVivo != 200*time.Millisecond {
+		t.Errorf("Expected EsperaVivo 200ms, but got %v", validadorConfig.EsperaVivo)
+	}
 	if !validadorConfig.LimpiarEntrada {
 		t.Errorf("Expected LimpiarEntrada true, but got %v", validadorConfig.LimpiarEntrada)
 	}
+	if !validadorConfig.TodosLosErrores {
+		t.Errorf("Expected TodosLosErrores true, but got %v", validadorConfig.TodosLosErrores)
+	}
+	if validadorConfig.PuntuacionEstricta {
+		t.Errorf("Expected PuntuacionEstricta false, but got %v", validadorConfig.PuntuacionEstricta)
+	}
 }
 
 func TestErrorAnalisis_Error(t *testing.T) {
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -27,63 +30,207 @@ const (
 	TipoNegativo       // Nuevas construcciones negativas
 	TipoCausaEfecto    // Nuevas frases de causa y efecto
 	TipoRespuestaCorta // Respuestas cortas
+	TipoInterrogativo  // Palabras interrogativas (what, where, ...)
 )
 
+// nombresTipo son los nombres estables de cada tipo de palabra, en el orden de
+// las constantes; se usan en JSON y no deben cambiar aunque se añadan tipos
+var nombresTipo = [...]string{
+	TipoDesconocido:      "unknown",
+	TipoSujeto:           "subject",
+	TipoVerboSimple:      "simple_verb",
+	TipoVerboEstado:      "state_verb",
+	TipoVerboAuxiliar:    "auxiliary_verb",
+	TipoVerboModalPasado: "past_modal",
+	TipoComplemento:      "complement",
+	TipoTiempo:           "time",
+	TipoPreposicion:      "preposition",
+	TipoArticulo:         "article",
+	TipoAdjetivo:         "adjective",
+	TipoAdverbio:         "adverb",
+	TipoConjuncion:       "conjunction",
+	TipoPronombre:        "pronoun",
+	TipoPuntuacion:       "punctuation",
+	TipoNegativo:         "negative",
+	TipoCausaEfecto:      "cause_effect",
+	TipoRespuestaCorta:   "short_answer",
+	TipoInterrogativo:    "interrogative",
+}
+
+// TiposPalabra devuelve todos los tipos de palabra en el orden de sus valores
+func TiposPalabra() []TipoPalabra {
+	tipos := make([]TipoPalabra, len(nombresTipo))
+	for i := range tipos {
+		tipos[i] = TipoPalabra(i)
+	}
+	return tipos
+}
+
+// ParseTipoPalabra interpreta el nombre estable de un tipo de palabra
+func ParseTipoPalabra(nombre string) (TipoPalabra, error) {
+	for i, n := range nombresTipo {
+		if n == nombre {
+			return TipoPalabra(i), nil
+		}
+	}
+	return TipoDesconocido, fmt.Errorf("unknown word type %q", nombre)
+}
+
+// String devuelve el nombre estable del tipo ("subject", "simple_verb", ...)
+func (t TipoPalabra) String() string {
+	if int(t) < len(nombresTipo) {
+		return nombresTipo[t]
+	}
+	return "TipoPalabra(" + strconv.Itoa(int(t)) + ")"
+}
+
+// MarshalText codifica el tipo con su nombre estable
+func (t TipoPalabra) MarshalText() ([]byte, error) {
+	if int(t) >= len(nombresTipo) {
+		return nil, fmt.Errorf("unknown word type %d", uint8(t))
+	}
+	return []byte(nombresTipo[t]), nil
+}
+
+// UnmarshalText decodifica el nombre estable de un tipo
+func (t *TipoPalabra) UnmarshalText(texto []byte) error {
+	tipo, err := ParseTipoPalabra(string(texto))
+	if err != nil {
+		return err
+	}
+	*t = tipo
+	return nil
+}
+
+// MarshalJSON codifica el tipo como una cadena con su nombre estable
+func (t TipoPalabra) MarshalJSON() ([]byte, error) {
+	texto, err := t.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(texto))
+}
+
+// UnmarshalJSON acepta el nombre estable del tipo y, para los clientes que aún
+// envían el valor numérico, también el número
+func (t *TipoPalabra) UnmarshalJSON(datos []byte) error {
+	if string(datos) == "null" {
+		return nil
+	}
+
+	var nombre string
+	if err := json.Unmarshal(datos, &nombre); err == nil {
+		return t.UnmarshalText([]byte(nombre))
+	}
+
+	var valor uint8
+	if err := json.Unmarshal(datos, &valor); err != nil {
+		return fmt.Errorf("word type must be a name or a number: %s", datos)
+	}
+	if int(valor) >= len(nombresTipo) {
+		return fmt.Errorf("unknown word type %d", valor)
+	}
+	*t = TipoPalabra(valor)
+	return nil
+}
+
+// DescripcionTipo describe un tipo de palabra en el listado de la API
+type DescripcionTipo struct {
+	Nombre string `json:"name"`
+	Valor  uint8  `json:"value"` // Valor numérico que usaban las versiones anteriores
+}
+
 // Config contiene la configuración de la aplicación
 type Config struct {
 	Port            string
-	StaticDir       string
-	TemplatesDir    string
+	StaticDir       string // Directorio de archivos estáticos; vacío usa los incluidos en el binario
+	TemplatesDir    string // Directorio de plantillas; vacío usa las incluidas en el binario
 	MaxRequestSize  int64
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	RequestTimeout  time.Duration
 	MaxOraciones    int
+	MaxLote         int
 	EnableCORS      bool
 	EnableRateLimit bool
+	// RutaDiccionario es el archivo de palabras que usa el validador; vacío usa
+	// el words.json incluido en el binario
+	RutaDiccionario string
+	// IntervaloDiccionario es cada cuánto se comprueba si el archivo de palabras cambió (0 no lo vigila)
+	IntervaloDiccionario time.Duration
+	// AdminToken protege los endpoints de administración; vacío los desactiva
+	AdminToken string
 }
 
 // NewConfig crea una nueva configuración con valores por defecto
 func NewConfig() *Config {
 	return &Config{
 		Port:            "8080",
-		StaticDir:       "static",
-		TemplatesDir:    "templates",
 		MaxRequestSize:  1 << 20,          // 1 MB
 		ReadTimeout:     5 * time.Second,  // 5 segundos
 		WriteTimeout:    10 * time.Second, // 10 segundos
 		RequestTimeout:  30 * time.Second, // 30 segundos
 		MaxOraciones:    5,                // Número máximo de oraciones
+		MaxLote:         100,              // Número máximo de oraciones por lote
 		EnableCORS:      true,             // Habilitar CORS por defecto
 		EnableRateLimit: true,             // Habilitar límite de tasa por defecto
+		// Comprobar cada 5 segundos si cambió el diccionario
+		IntervaloDiccionario: 5 * time.Second,
 	}
 }
 
 // ValidadorConfig contiene la configuración del validador
 type ValidadorConfig struct {
-	MinPalabras    int  // Número mínimo de palabras
-	MaxPalabras    int  // Número máximo de palabras
-	MaxOraciones   int  // Número máximo de oraciones
-	LimpiarEntrada bool // Si se debe limpiar la entrada
+	MinPalabras        int           // Número mínimo de palabras
+	MaxPalabras        int           // Número máximo de palabras
+	MaxOraciones       int           // Número máximo de oraciones
+	MaxLote            int           // Número máximo de oraciones en una validación por lotes
+	Trabajadores       int           // Oraciones que se validan en paralelo; 0 usa uno por procesador
+	PlazoLinea         time.Duration // Plazo para leer y escribir cada línea de una validación en streaming
+	EsperaVivo         time.Duration // Tiempo sin cambios antes de validar el texto en vivo
+	LimpiarEntrada     bool          // Si se debe limpiar la entrada
+	TodosLosErrores    bool          // Si se reportan todos los errores de cada oración
+	PuntuacionEstricta bool          // Si se exige mayúscula inicial y signo final en todas las oraciones
 }
 
 // NewValidadorConfig crea una nueva instancia de ValidadorConfig con valores por defecto
 func NewValidadorConfig() ValidadorConfig {
 	return ValidadorConfig{
-		MinPalabras:    1,
-		MaxPalabras:    50,
-		MaxOraciones:   5,
-		LimpiarEntrada: true,
+		MinPalabras:     1,
+		MaxPalabras:     50,
+		MaxOraciones:    5,
+		MaxLote:         100,
+		Trabajadores:    0, // Uno por procesador (GOMAXPROCS)
+		PlazoLinea:      10 * time.Second,
+		EsperaVivo:      200 * time.Millisecond,
+		LimpiarEntrada:  true,
+		TodosLosErrores: true,
+		// La puntuación estricta la activa cada docente al validar
+		PuntuacionEstricta: false,
 	}
 }
 
+// FormaVerbal identifica la inflexión de un verbo
+type FormaVerbal string
+
+const (
+	FormaBase           FormaVerbal = "base"
+	FormaPasado         FormaVerbal = "pasado"
+	FormaParticipio     FormaVerbal = "participio"
+	FormaGerundio       FormaVerbal = "gerundio"
+	FormaTerceraPersona FormaVerbal = "tercera_persona"
+)
+
 // Metadata almacena información adicional sobre la palabra
 type Metadata struct {
-	EsNombrePropio bool
-	EsAbreviatura  bool
-	EsContraccion  bool
-	SubTipo        string
-	EsVerboEstado  bool
+	EsNombrePropio   bool
+	EsAbreviatura    bool
+	EsContraccion    bool // Parte de una contracción; sin Original si no es la primera ("not" en "wasn't")
+	SubTipo          string
+	EsVerboEstado    bool
+	Lema             string      // Forma base del verbo
+	Forma            FormaVerbal // Inflexión del verbo
+	PegadoAlAnterior bool        // Escrito sin espacio tras el token anterior ("," en "yesterday,")
 }
 
 // Palabra representa una palabra con su tipo y metadata adicional
@@ -102,6 +249,48 @@ type Token struct {
 	Original string
 	Posicion int
 	Metadata Metadata
+
+	// Ubicación en el texto analizado, en bytes y en runas (fin exclusivo)
+	ByteInicio int
+	ByteFin    int
+	RunaInicio int
+	RunaFin    int
+}
+
+// Severidad indica la gravedad de un diagnóstico
+type Severidad string
+
+const (
+	SeveridadError       Severidad = "error"
+	SeveridadAdvertencia Severidad = "warning"
+)
+
+// Rango delimita un fragmento de la oración en palabras, bytes y runas (fin exclusivo)
+type Rango struct {
+	PalabraInicio int `json:"palabra_inicio"`
+	PalabraFin    int `json:"palabra_fin"`
+	ByteInicio    int `json:"byte_inicio"`
+	ByteFin       int `json:"byte_fin"`
+	RunaInicio    int `json:"runa_inicio"`
+	RunaFin       int `json:"runa_fin"`
+}
+
+// Diagnostic describe un problema encontrado en una oración
+type Diagnostic struct {
+	Regla      string    `json:"regla"` // Identificador estable de la regla
+	Severidad  Severidad `json:"severidad"`
+	Mensaje    string    `json:"mensaje"`
+	Rango      Rango     `json:"rango"`
+	Sugerencia string    `json:"sugerencia,omitempty"` // Texto que reemplaza al rango
+	Correccion string    `json:"correccion,omitempty"` // Oración reescrita con la sugerencia
+}
+
+// Segmento es una oración dentro de un texto más largo; Inicio y Fin son bytes
+// del texto original (fin exclusivo)
+type Segmento struct {
+	Texto  string `json:"texto"`
+	Inicio int    `json:"inicio"`
+	Fin    int    `json:"fin"`
 }
 
 // ElementoOracion representa el estado de un elemento dentro de una oración
@@ -113,10 +302,132 @@ type ElementoOracion struct {
 
 // ResultadoOracion representa el resultado de la validación de una oración
 type ResultadoOracion struct {
-	Oracion     string
-	EsValida    bool
-	Mensaje     string
-	Explicacion string
+	Oracion      string
+	Segmento     Segmento // Oración tal como se escribió; los rangos de los diagnósticos apuntan a su texto
+	EsValida     bool
+	Mensaje      string
+	Explicacion  string
+	Correccion   string // Oración con todas las sugerencias aplicadas
+	Diagnosticos []Diagnostic
+	Tokens       []Token     // Tokens con sus posiciones en Segmento.Texto
+	Fragmentos   []Fragmento // Segmento.Texto dividido para resaltar cada token
+}
+
+// Fragmento es un trozo del texto escrito: un token, con su categoría y los
+// errores que lo señalan, o el texto que queda entre dos tokens
+type Fragmento struct {
+	Texto     string   `json:"texto"`
+	Clase     string   `json:"clase,omitempty"`     // Identificador de la categoría del token ("" fuera de los tokens)
+	Categoria string   `json:"categoria,omitempty"` // Nombre de la categoría que se muestra al estudiante
+	Errores   []string `json:"errores,omitempty"`   // Mensajes de los diagnósticos que abarcan el token
+}
+
+// CategoriaToken es una entrada de la leyenda de colores de los resultados
+type CategoriaToken struct {
+	Clase  string
+	Nombre string
+}
+
+// PeticionLoteOraciones es el cuerpo de POST /api/validar/batch: una lista de
+// oraciones o un párrafo que se divide en oraciones
+type PeticionLoteOraciones struct {
+	Oraciones          []string `json:"oraciones,omitempty"`
+	Parrafo            string   `json:"parrafo,omitempty"`
+	Perfil             string   `json:"perfil,omitempty"`
+	PuntuacionEstricta bool     `json:"puntuacion_estricta,omitempty"`
+}
+
+// RespuestaLoteOraciones contiene un resultado por oración, en el orden recibido,
+// y las estadísticas del lote
+type RespuestaLoteOraciones struct {
+	Resultados   []ResultadoLoteOracion `json:"resultados"`
+	Estadisticas EstadisticasLote       `json:"estadisticas"`
+}
+
+// ResultadoLoteOracion es la validación de una oración del lote o, si no se pudo
+// validar, el error que lo impidió
+type ResultadoLoteOracion struct {
+	Indice       int          `json:"indice"`
+	Linea        int          `json:"linea,omitempty"` // Línea del cuerpo en una validación en streaming
+	Oracion      string       `json:"oracion"`
+	Segmento     *Segmento    `json:"segmento,omitempty"` // Posición en el párrafo recibido
+	EsValida     bool         `json:"es_valida"`
+	Mensaje      string       `json:"mensaje,omitempty"`
+	Explicacion  string       `json:"explicacion,omitempty"`
+	Correccion   string       `json:"correccion,omitempty"`
+	Diagnosticos []Diagnostic `json:"diagnosticos,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// EstadisticasLote resume un lote; el porcentaje de éxito y los errores comunes
+// solo cuentan las oraciones que se pudieron validar
+type EstadisticasLote struct {
+	Total           int            `json:"total"`
+	Validas         int            `json:"validas"`
+	Invalidas       int            `json:"invalidas"`
+	Fallidas        int            `json:"fallidas"`
+	PorcentajeExito float64        `json:"porcentaje_exito"`
+	ErroresComunes  map[string]int `json:"errores_comunes"`
+}
+
+// PeticionVivo es el cuerpo de POST /api/live: el texto que el estudiante lleva
+// escrito en una sesión de validación en vivo
+type PeticionVivo struct {
+	Sesion             string `json:"sesion"`
+	Texto              string `json:"texto"`
+	Version            int    `json:"version"` // Número creciente que el cliente usa para descartar resultados viejos
+	Perfil             string `json:"profile,omitempty"`
+	PuntuacionEstricta bool   `json:"strict_punctuation,omitempty"`
+}
+
+// ResultadoVivo es el evento que la validación en vivo envía por SSE para la
+// última versión del texto
+type ResultadoVivo struct {
+	Version   int           `json:"version"`
+	Oraciones []OracionVivo `json:"oraciones"`
+	Error     string        `json:"error,omitempty"` // El texto no se puede validar (por ejemplo, demasiadas oraciones)
+}
+
+// OracionVivo es una oración del texto validado en vivo, con su posición en el
+// texto y sus fragmentos resaltados
+type OracionVivo struct {
+	Segmento
+	EsValida     bool         `json:"es_valida"`
+	Mensaje      string       `json:"mensaje,omitempty"`
+	Explicacion  string       `json:"explicacion,omitempty"`
+	Correccion   string       `json:"correccion,omitempty"`
+	Diagnosticos []Diagnostic `json:"diagnosticos"`
+	Fragmentos   []Fragmento  `json:"fragmentos"`
+}
+
+// EstadoDiccionario describe el diccionario en uso para la comprobación de
+// disponibilidad
+type EstadoDiccionario struct {
+	Listo    bool       `json:"listo"` // Hay un diccionario cargado desde un archivo
+	Ruta     string     `json:"ruta"`
+	Entradas int        `json:"entradas"`
+	Cargado  *time.Time `json:"cargado,omitempty"`
+	Error    string     `json:"error,omitempty"` // Error de la última carga; el diccionario anterior sigue en uso
+}
+
+// CategoriaDiccionario es una lista de palabras del archivo de palabras,
+// nombrada por su ruta en el JSON ("complementos.lugares")
+type CategoriaDiccionario struct {
+	Categoria string   `json:"categoria"`
+	Palabras  []string `json:"palabras"`
+}
+
+// EntradaCategoria es una palabra del archivo de palabras junto con su categoría
+type EntradaCategoria struct {
+	Palabra   string `json:"palabra"`
+	Categoria string `json:"categoria"`
+}
+
+// PeticionPalabra agrega o cambia una palabra desde la administración del
+// diccionario; al cambiarla, una categoría vacía la deja en la que estaba
+type PeticionPalabra struct {
+	Palabra   string `json:"palabra"`
+	Categoria string `json:"categoria,omitempty"`
 }
 
 // Estadisticas contiene estadísticas sobre las validaciones realizadas
@@ -128,12 +439,22 @@ type Estadisticas struct {
 
 // PageVariables contiene las variables para renderizar la plantilla
 type PageVariables struct {
-	Oraciones        []ResultadoOracion
-	TotalOraciones   int
-	OracionesValidas int
-	ShowResults      bool
-	ErrorMessage     string
-	Estadisticas     Estadisticas
+	Oraciones          []ResultadoOracion
+	TotalOraciones     int
+	OracionesValidas   int
+	ShowResults        bool
+	ErrorMessage       string
+	Estadisticas       Estadisticas
+	Perfiles           []OpcionPerfil   // Ejercicios disponibles en el selector
+	Perfil             string           // Ejercicio seleccionado
+	PuntuacionEstricta bool             // Si se pidió la revisión estricta de puntuación
+	Leyenda            []CategoriaToken // Categorías de token que aparecen en los resultados
+}
+
+// OpcionPerfil describe un perfil de ejercicio para el selector de la página
+type OpcionPerfil struct {
+	Nombre      string
+	Descripcion string
 }
 
 // Contexto almacena información sobre el contexto de análisis
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -55,3 +57,58 @@ func TestValidarPalabra(t *testing.T) {
 		})
 	}
 }
+
+// TestTipoPalabraJSON verifica que los tipos se codifiquen con su nombre estable
+// y que se acepten tanto los nombres como los valores numéricos antiguos
+func TestTipoPalabraJSON(t *testing.T) {
+	datos, err := json.Marshal(Token{Tipo: TipoVerboSimple, Texto: "played"})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error = %v", err)
+	}
+	if !strings.Contains(string(datos), `"Tipo":"simple_verb"`) {
+		t.Errorf("json.Marshal() = %s, expected the type name", datos)
+	}
+
+	tests := []struct {
+		name     string
+		entrada  string
+		expected TipoPalabra
+		wantErr  bool
+	}{
+		{"name", `"subject"`, TipoSujeto, false},
+		{"number", `6`, TipoComplemento, false},
+		{"unknown name", `"noun"`, TipoDesconocido, true},
+		{"number out of range", `200`, TipoDesconocido, true},
+		{"wrong kind", `true`, TipoDesconocido, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tipo TipoPalabra
+			err := json.Unmarshal([]byte(tt.entrada), &tipo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal(%s) error = %v, wantErr %v", tt.entrada, err, tt.wantErr)
+			}
+			if tipo != tt.expected {
+				t.Errorf("json.Unmarshal(%s) = %v, want %v", tt.entrada, tipo, tt.expected)
+			}
+		})
+	}
+
+	// Todos los tipos tienen un nombre propio que se decodifica al mismo valor
+	vistos := make(map[string]bool)
+	for _, tipo := range TiposPalabra() {
+		nombre := tipo.String()
+		if vistos[nombre] || strings.HasPrefix(nombre, "TipoPalabra(") {
+			t.Errorf("type %d has no unique name: %q", tipo, nombre)
+		}
+		vistos[nombre] = true
+
+		if got, err := ParseTipoPalabra(nombre); err != nil || got != tipo {
+			t.Errorf("ParseTipoPalabra(%q) = %v, %v, want %v", nombre, got, err, tipo)
+		}
+	}
+	if got := TipoPalabra(200).String(); got != "TipoPalabra(200)" {
+		t.Errorf("String() of an unknown type = %q", got)
+	}
+}
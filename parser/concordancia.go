@@ -0,0 +1,85 @@
+package validators
+
+import (
+	"strings"
+	"validar_oraciones/models"
+)
+
+// esTerceraPersonaSingular indica si el sujeto concuerda con la tercera persona
+// del singular ("he", "John", "the teacher") y no con el plural ("my parents")
+func esTerceraPersonaSingular(sujeto string) bool {
+	sujeto = strings.ToLower(sujeto)
+	switch sujeto {
+	case "i", "you", "we", "they":
+		return false
+	case "he", "she", "it":
+		return true
+	}
+
+	// Los nombres propios son singulares; en los grupos nominales decide el sustantivo
+	if !strings.Contains(sujeto, " ") {
+		return true
+	}
+	sustantivo := sujeto[strings.LastIndex(sujeto, " ")+1:]
+	switch {
+	case sustantivo == "children" || sustantivo == "people":
+		return false
+	case strings.HasSuffix(sustantivo, "s") && !strings.HasSuffix(sustantivo, "ss"):
+		return false
+	}
+	return true
+}
+
+// formaSer devuelve la forma de "to be" que concuerda con el sujeto, en
+// presente (am/is/are) o en pasado (was/were)
+func formaSer(sujeto string, pasado bool) string {
+	switch {
+	case strings.EqualFold(sujeto, "i"):
+		if pasado {
+			return "was"
+		}
+		return "am"
+	case esTerceraPersonaSingular(sujeto):
+		if pasado {
+			return "was"
+		}
+		return "is"
+	case pasado:
+		return "were"
+	}
+	return "are"
+}
+
+// esFormaSer indica si el texto es una forma de "to be" en presente o pasado
+func esFormaSer(texto string) bool {
+	switch texto {
+	case "am", "is", "are", "was", "were":
+		return true
+	}
+	return false
+}
+
+// diagnosticoFormaSer crea el diagnóstico para la forma de "to be" del token i y
+// sugiere la esperada conservando la contracción que la rodea
+// ("wasn't" -> "weren't", "I'm" -> "I was")
+func diagnosticoFormaSer(tokens []models.Token, i int, regla, mensaje, esperado string) models.Diagnostic {
+	desde, hasta := i, i+1
+	sugerencia := igualarMayuscula(textoToken(tokens[i]), esperado)
+	if i > 0 && esContinuacion(tokens[i]) {
+		desde = i - 1
+		sugerencia = igualarMayuscula(textoToken(tokens[desde]), tokens[desde].Texto) + " " + esperado
+	}
+	if hasta < len(tokens) && esContinuacion(tokens[hasta]) && tokens[hasta].Texto == "not" {
+		hasta++
+		sugerencia += "n't"
+	}
+
+	diagnostico := nuevoDiagnostico(regla, mensaje, tokens, desde, hasta)
+	diagnostico.Sugerencia = sugerencia
+	return diagnostico
+}
+
+// esWasWere indica si el token es el pasado de "to be"
+func esWasWere(token models.Token) bool {
+	return token.Texto == "was" || token.Texto == "were"
+}
@@ -0,0 +1,113 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"validar_oraciones/models"
+)
+
+// diagnosticarContinuo aplica las reglas del pasado continuo:
+// sujeto + was/were + verbo en -ing + complemento
+func diagnosticarContinuo(tokens []models.Token, v *validacion) []models.Diagnostic {
+	if len(tokens) == 0 {
+		v.reportar(nuevoDiagnostico(ReglaSinTokens, "No tokens found.", tokens, 0, 0))
+		return v.diagnosticos
+	}
+
+	sujeto, auxiliar := -1, -1
+	for i, token := range tokens {
+		switch {
+		case token.Tipo == models.TipoSujeto && sujeto == -1:
+			sujeto = i
+		case esFormaSer(token.Texto) && auxiliar == -1:
+			auxiliar = i
+		}
+	}
+
+	if sujeto == -1 {
+		if v.reportar(nuevoDiagnostico(ReglaSujetoFaltante,
+			"The subject is missing in the sentence.", tokens, 0, len(tokens))) {
+			return v.diagnosticos
+		}
+	}
+
+	if auxiliar == -1 {
+		v.reportar(nuevoDiagnostico(ReglaWasWereFaltante,
+			"A past continuous sentence needs 'was' or 'were' before the verb.", tokens, 0, len(tokens)))
+		return v.diagnosticos
+	}
+
+	if sujeto != -1 {
+		if sujeto > auxiliar {
+			if v.reportar(nuevoDiagnostico(ReglaOrdenSujetoVerbo,
+				fmt.Sprintf("The subject must come before '%s'.", tokens[auxiliar].Texto), tokens, sujeto, sujeto+1)) {
+				return v.diagnosticos
+			}
+		} else if esperado := formaSer(tokens[sujeto].Texto, true); tokens[auxiliar].Texto != esperado {
+			regla, mensaje := ReglaConcordanciaWasWere, fmt.Sprintf("Incorrect verb form for '%s'. Use '%s'.", nombreSujeto(textoToken(tokens[sujeto])), esperado)
+			if tokens[auxiliar].Texto != "was" && tokens[auxiliar].Texto != "were" {
+				regla, mensaje = ReglaTiempoVerbal, fmt.Sprintf("'%s' is not in the past. Use '%s'.", tokens[auxiliar].Texto, esperado)
+			}
+			if v.reportar(diagnosticoFormaSer(tokens, auxiliar, regla, mensaje, esperado)) {
+				return v.diagnosticos
+			}
+		}
+	}
+
+	// Ensure complements come after the verb
+	for i := 0; i < auxiliar; i++ {
+		if tokens[i].Tipo == models.TipoComplemento {
+			if v.reportar(nuevoDiagnostico(ReglaComplementoAntesDelVerbo,
+				"The complement must come after the verb.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+			break
+		}
+	}
+
+	// The -ing verb follows was/were, optionally after "not" or an adverb
+	// ("wasn't playing", "was quickly running")
+	verbo := auxiliar + 1
+	for verbo < len(tokens) && (tokens[verbo].Tipo == models.TipoAdverbio || tokens[verbo].Texto == "not") {
+		verbo++
+	}
+	if verbo == len(tokens) || !esVerbo(tokens[verbo]) {
+		v.reportar(nuevoDiagnostico(ReglaGerundioFaltante,
+			fmt.Sprintf("A verb ending in -ing is missing after '%s'.", tokens[auxiliar].Texto),
+			tokens, auxiliar, auxiliar+1))
+		return v.diagnosticos
+	}
+
+	if diagnostico, existe := diagnosticoGerundio(v.diccionario, tokens, auxiliar, verbo); existe {
+		v.reportar(diagnostico)
+	}
+
+	return v.diagnosticos
+}
+
+// diagnosticoGerundio comprueba que el verbo que sigue a was/were termine en
+// -ing ("was running", no "was ran") y sugiere el gerundio correcto
+func diagnosticoGerundio(d *Dictionary, tokens []models.Token, auxiliar, verbo int) (models.Diagnostic, bool) {
+	token := tokens[verbo]
+	if token.Metadata.Forma == models.FormaGerundio || strings.HasSuffix(token.Texto, "ing") {
+		return models.Diagnostic{}, false
+	}
+
+	lema := token.Metadata.Lema
+	if pasado, existe := d.formaSobrerregularizada(token.Texto); existe {
+		lema, _ = d.Lemma(pasado)
+	}
+
+	formas, existe := d.FormasDe(lema)
+	if !existe || formas.Gerundio == "" {
+		return nuevoDiagnostico(ReglaGerundio,
+			fmt.Sprintf("After '%s' the verb must end in -ing.", tokens[auxiliar].Texto),
+			tokens, verbo, verbo+1), true
+	}
+
+	diagnostico := nuevoDiagnostico(ReglaGerundio,
+		fmt.Sprintf("After '%s' use the -ing form '%s', not '%s'.", tokens[auxiliar].Texto, formas.Gerundio, token.Texto),
+		tokens, verbo, verbo+1)
+	diagnostico.Sugerencia = formas.Gerundio
+	return diagnostico, true
+}
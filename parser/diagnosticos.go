@@ -0,0 +1,226 @@
+package validators
+
+import (
+	"strings"
+	"unicode/utf8"
+	"validar_oraciones/models"
+)
+
+// Identificadores estables de las reglas de validación. Los mensajes pueden
+// cambiar de redacción, pero estos valores no.
+const (
+	ReglaSinTokens                = "no-tokens"
+	ReglaAnalisisLexico           = "lexical-error"
+	ReglaLongitud                 = "sentence-length" // Usada por quienes limitan el número de palabras
+	ReglaNegacion                 = "negative-not-allowed"
+	ReglaAuxiliar                 = "auxiliary-not-allowed"
+	ReglaSujetoFaltante           = "missing-subject"
+	ReglaPronombreDesconocido     = "unknown-subject-pronoun"
+	ReglaConcordanciaWasWere      = "was-were-agreement"
+	ReglaOrdenSujetoVerbo         = "subject-verb-order"
+	ReglaVerboTrasSujeto          = "verb-after-subject"
+	ReglaVerboFaltante            = "missing-past-verb"
+	ReglaComplementoAntesDelVerbo = "complement-before-verb"
+	ReglaPasadoIrregular          = "irregular-past"
+	ReglaTiempoVerbal             = "tense-mismatch"
+	ReglaNegacionFaltante         = "missing-negation"
+	ReglaVerboBaseFaltante        = "missing-base-verb"
+	ReglaVerboBase                = "base-verb-required"
+	ReglaInversionPregunta        = "question-inversion"
+	ReglaSignoPregunta            = "question-mark"
+	ReglaVerboPresenteFaltante    = "missing-present-verb"
+	ReglaConcordancia             = "subject-verb-agreement"
+	ReglaWasWereFaltante          = "missing-was-were"
+	ReglaGerundioFaltante         = "missing-gerund"
+	ReglaGerundio                 = "gerund-required"
+	ReglaMayuscula                = "capitalization"
+	ReglaSignoFinal               = "terminal-punctuation"
+	ReglaPuntuacionMalColocada    = "misplaced-punctuation"
+)
+
+// Explicaciones que acompañan a una oración sin errores
+const (
+	MensajeValido         = "The sentence has a valid structure in the simple past affirmative."
+	MensajeValidoNegativo = "The sentence has a valid structure in the simple past negative."
+	MensajeValidoPregunta = "The sentence has a valid structure in the simple past interrogative."
+	MensajeValidoPresente = "The sentence has a valid structure in the simple present."
+	MensajeValidoContinuo = "The sentence has a valid structure in the past continuous."
+)
+
+// ExplicacionValida devuelve la explicación de una oración válida según el perfil
+func ExplicacionValida(opciones ...Opcion) string {
+	return aplicarOpciones(opciones).perfil.Explicacion
+}
+
+// EsValida indica si ningún diagnóstico tiene severidad de error
+func EsValida(diagnosticos []models.Diagnostic) bool {
+	_, existe := primerError(diagnosticos)
+	return !existe
+}
+
+// primerError devuelve el primer diagnóstico con severidad de error
+func primerError(diagnosticos []models.Diagnostic) (models.Diagnostic, bool) {
+	for _, d := range diagnosticos {
+		if d.Severidad == models.SeveridadError {
+			return d, true
+		}
+	}
+	return models.Diagnostic{}, false
+}
+
+// validacion acumula los diagnósticos producidos al validar una oración
+type validacion struct {
+	diccionario  *Dictionary
+	opciones     opciones
+	diagnosticos []models.Diagnostic
+}
+
+// nuevaValidacion prepara una validación con el diccionario y las opciones recibidas
+func nuevaValidacion(d *Dictionary, lista []Opcion) *validacion {
+	return &validacion{diccionario: d, opciones: aplicarOpciones(lista)}
+}
+
+// reportar registra un diagnóstico y devuelve true si la validación debe detenerse
+func (v *validacion) reportar(diagnostico models.Diagnostic) bool {
+	v.diagnosticos = append(v.diagnosticos, diagnostico)
+	return diagnostico.Severidad == models.SeveridadError && !v.opciones.todosLosErrores
+}
+
+// detenida indica si la validación ya encontró el error que la detiene
+func (v *validacion) detenida() bool {
+	return !v.opciones.todosLosErrores && !EsValida(v.diagnosticos)
+}
+
+// nuevoDiagnostico crea un error que abarca los tokens [desde, hasta)
+func nuevoDiagnostico(regla, mensaje string, tokens []models.Token, desde, hasta int) models.Diagnostic {
+	return models.Diagnostic{
+		Regla:     regla,
+		Severidad: models.SeveridadError,
+		Mensaje:   mensaje,
+		Rango:     rangoTokens(tokens, desde, hasta),
+	}
+}
+
+// rangoTokens calcula el rango en palabras y bytes de los tokens [desde, hasta).
+// Los bytes se cuentan sobre el texto que reconstruye textoTokens.
+func rangoTokens(tokens []models.Token, desde, hasta int) models.Rango {
+	if desde >= len(tokens) {
+		return models.Rango{}
+	}
+
+	rangos := rangosPorToken(tokens)
+	rango := rangos[desde]
+	if desde >= hasta {
+		rango.PalabraFin, rango.ByteFin = rango.PalabraInicio, rango.ByteInicio
+	} else {
+		ultimo := rangos[min(hasta, len(tokens))-1]
+		rango.PalabraFin, rango.ByteFin = ultimo.PalabraFin, ultimo.ByteFin
+	}
+
+	completarRunas(&rango, textoTokens(tokens))
+	return rango
+}
+
+// completarRunas calcula las posiciones en runas de un rango a partir de sus bytes
+func completarRunas(rango *models.Rango, texto string) {
+	if rango.ByteInicio < 0 || rango.ByteFin > len(texto) || rango.ByteInicio > rango.ByteFin {
+		return
+	}
+	rango.RunaInicio = utf8.RuneCountInString(texto[:rango.ByteInicio])
+	rango.RunaFin = rango.RunaInicio + utf8.RuneCountInString(texto[rango.ByteInicio:rango.ByteFin])
+}
+
+// rangosPorToken ubica cada token en el texto reconstruido. Los tokens escritos
+// sin espacio ("," en "yesterday,") cuentan como parte de la palabra anterior y
+// las partes de una contracción comparten su rango.
+func rangosPorToken(tokens []models.Token) []models.Rango {
+	rangos := make([]models.Rango, len(tokens))
+	palabra, byteActual := 0, 0
+
+	for i, token := range tokens {
+		texto := textoToken(token)
+		switch {
+		case i > 0 && esContinuacion(token):
+			rangos[i] = rangos[i-1]
+			continue
+		case i > 0 && token.Metadata.PegadoAlAnterior:
+			byteActual-- // Sin espacio separador
+			rangos[i] = models.Rango{PalabraInicio: palabra - 1, PalabraFin: palabra, ByteInicio: byteActual, ByteFin: byteActual + len(texto)}
+		default:
+			n := max(len(strings.Fields(texto)), 1)
+			rangos[i] = models.Rango{PalabraInicio: palabra, PalabraFin: palabra + n, ByteInicio: byteActual, ByteFin: byteActual + len(texto)}
+			palabra += n
+		}
+		byteActual = rangos[i].ByteFin + 1 // Espacio separador
+	}
+
+	return rangos
+}
+
+// textoToken devuelve el texto original del token o, si no existe, el normalizado
+func textoToken(token models.Token) string {
+	if token.Original != "" || esContinuacion(token) {
+		return token.Original
+	}
+	return token.Texto
+}
+
+// esContinuacion indica si el token es una parte de una contracción sin texto
+// propio, como "not" en "wasn't"
+func esContinuacion(token models.Token) bool {
+	return token.Metadata.EsContraccion && token.Original == ""
+}
+
+// DiagnosticarOracion analiza y valida la oración completa con el validador
+// por defecto; los rangos de los diagnósticos apuntan al texto recibido
+func DiagnosticarOracion(oracion string, opciones ...Opcion) []models.Diagnostic {
+	return porDefecto.DiagnosticarOracion(oracion, opciones...)
+}
+
+// DiagnosticarOracion analiza y valida la oración completa; los rangos de los
+// diagnósticos apuntan al texto recibido
+func (v *Validator) DiagnosticarOracion(oracion string, opciones ...Opcion) []models.Diagnostic {
+	// El análisis y la validación usan el mismo diccionario aunque se recargue
+	d := v.Diccionario()
+	tokens, err := d.analizarLexico(oracion)
+	if err != nil {
+		return []models.Diagnostic{{
+			Regla:     ReglaAnalisisLexico,
+			Severidad: models.SeveridadError,
+			Mensaje:   err.Error(),
+		}}
+	}
+
+	diagnosticos := diagnosticar(d, tokens, v.con(opciones))
+	ubicarEnOriginal(diagnosticos, tokens, oracion)
+	completarCorrecciones(diagnosticos, oracion)
+	return diagnosticos
+}
+
+// ubicarEnOriginal traduce los rangos calculados sobre el texto reconstruido de
+// los tokens a las posiciones que el análisis léxico registró en la oración
+func ubicarEnOriginal(diagnosticos []models.Diagnostic, tokens []models.Token, oracion string) {
+	rangos := rangosPorToken(tokens)
+
+	for i := range diagnosticos {
+		rango := &diagnosticos[i].Rango
+		inicio, fin := -1, -1
+		for j, r := range rangos {
+			if inicio == -1 && r.ByteInicio == rango.ByteInicio {
+				inicio = tokens[j].ByteInicio
+			}
+			if r.ByteFin == rango.ByteFin {
+				fin = tokens[j].ByteFin
+			}
+		}
+		if rango.ByteInicio == rango.ByteFin {
+			fin = inicio
+		}
+		if inicio == -1 || fin == -1 {
+			continue
+		}
+
+		rango.ByteInicio, rango.ByteFin = inicio, fin
+		completarRunas(rango, oracion)
+	}
+}
@@ -0,0 +1,437 @@
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"validar_oraciones/models"
+)
+
+var (
+	// actual es el diccionario en uso; se sustituye entero al recargar
+	actual atomic.Pointer[Dictionary]
+	once   sync.Once
+	// recarga evita que dos cargas lean el archivo a la vez y protege la ruta
+	// y el último error
+	recarga    sync.Mutex
+	errorCarga error // Error de la última carga; nil si tuvo éxito
+
+	// Ruta del archivo de palabras, relativa al directorio de trabajo o a
+	// archivosDiccionario
+	rutaDiccionario = "words.json"
+	// archivosDiccionario es el sistema de archivos del que se lee
+	// rutaDiccionario; nil lee del disco
+	archivosDiccionario fs.FS
+	// editable indica que CargarDiccionario eligió un archivo del disco; solo
+	// entonces AgregarPalabra y las demás ediciones pueden escribirlo. La ruta
+	// por defecto nunca se edita.
+	editable bool
+)
+
+// Estructura para leer el JSON de palabras
+type WordsData struct {
+	Verbos struct {
+		Regulares   []string `json:"regulares"`
+		Irregulares struct {
+			VerbosComunes []string `json:"verbos_comunes"`
+			Auxiliares    []string `json:"verbos_auxiliares"`
+			VerbosEstado  []string `json:"verbos_estado"` // No se agrega al diccionario; se conserva al guardar
+		} `json:"irregulares"`
+		Formas []FormasVerbo `json:"formas"`
+	} `json:"verbos"`
+	Sujeto            []string            `json:"sujeto"`
+	Complementos      Complementos        `json:"complementos"`
+	Preposiciones     []string            `json:"preposiciones"`
+	Articulos         []string            `json:"articulos"`
+	Adjetivos         map[string][]string `json:"adjetivos"`
+	Adverbios         map[string][]string `json:"adverbios"`
+	ExpresionesTiempo []string            `json:"expresiones_tiempo"`
+	Negaciones        []string            `json:"negaciones"`
+	Interrogativos    []string            `json:"interrogativos"`
+	Contracciones     map[string]string   `json:"contracciones"`
+	ModalesPasados    []string            `json:"modales_pasados"` // Campo agregado para los verbos modales pasados
+}
+
+// Nuevo struct para modelar Complementos como un objeto en lugar de una lista
+type Complementos struct {
+	Objetos []string `json:"objetos"`
+	Lugares []string `json:"lugares"`
+	Comida  []string `json:"comida"`
+}
+
+// Dictionary es una versión completa del diccionario. No cambia después de
+// construirse, así que se lee sin bloqueos; al recargar se construye otra y se
+// sustituye de forma atómica.
+type Dictionary struct {
+	palabras       map[string]models.Palabra
+	longitudFrases map[string]int         // Palabra inicial -> número máximo de palabras de las expresiones que empiezan con ella
+	formasVerbales map[string]FormasVerbo // Forma base -> inflexiones
+	lemas          map[string]string      // Cualquier forma -> forma base
+	contracciones  map[string]string      // Contracción -> palabras que la forman ("wasn't" -> "was not")
+	datos          WordsData              // Listas de las que se construyó
+
+	ruta    string    // Archivo del que se cargó
+	cargado time.Time // Momento de la carga; cero en el diccionario vacío
+}
+
+// LoadDictionary lee y valida un archivo de palabras y construye un
+// diccionario. No cambia el diccionario que usa el paquete; para eso está
+// CargarDiccionario.
+func LoadDictionary(path string) (*Dictionary, error) {
+	return cargarDesde(nil, path)
+}
+
+// LoadDictionaryFS es como LoadDictionary pero lee el archivo de fsys, por
+// ejemplo de un embed.FS incluido en el binario
+func LoadDictionaryFS(fsys fs.FS, name string) (*Dictionary, error) {
+	return cargarDesde(fsys, name)
+}
+
+// cargarDesde lee y valida el archivo de palabras de fsys, o del disco si es nil
+func cargarDesde(fsys fs.FS, path string) (*Dictionary, error) {
+	wordsData, err := cargarPalabrasDesdeJSON(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := NewDictionary(wordsData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	d.ruta = path
+	return d, nil
+}
+
+// NewDictionary valida las palabras recibidas y construye un diccionario con
+// ellas, sin leer ningún archivo. Sirve para probar reglas con un vocabulario
+// propio o para usar varios vocabularios a la vez con NewValidator.
+func NewDictionary(wordsData WordsData) (*Dictionary, error) {
+	if err := wordsData.validar(); err != nil {
+		return nil, err
+	}
+	d := nuevoDiccionario(wordsData)
+	d.cargado = time.Now()
+	return d, nil
+}
+
+// Entradas devuelve el número de palabras y expresiones del diccionario
+func (d *Dictionary) Entradas() int {
+	return len(d.palabras)
+}
+
+// categoriaPalabras es una lista de WordsData junto con el tipo y la metadata
+// que reciben sus palabras
+type categoriaPalabras struct {
+	nombre   string // Ruta de la lista en el JSON
+	palabras []string
+	tipo     models.TipoPalabra
+	metadata models.Metadata
+}
+
+// categorias devuelve las listas de palabras en el orden en que se agregan al
+// diccionario; si una palabra se repite, gana la última categoría
+func (w WordsData) categorias() []categoriaPalabras {
+	return []categoriaPalabras{
+		{"sujeto", w.Sujeto, models.TipoSujeto, models.Metadata{}},
+		{"verbos.regulares", w.Verbos.Regulares, models.TipoVerboSimple, models.Metadata{}},
+		{"verbos.irregulares.verbos_comunes", w.Verbos.Irregulares.VerbosComunes, models.TipoVerboSimple, models.Metadata{SubTipo: SubTipoIrregular}},
+		{"verbos.irregulares.verbos_auxiliares", w.Verbos.Irregulares.Auxiliares, models.TipoVerboAuxiliar, models.Metadata{}},
+		{"adjetivos.estado", w.Adjetivos["estado"], models.TipoVerboEstado, models.Metadata{}},
+		{"modales_pasados", w.ModalesPasados, models.TipoVerboModalPasado, models.Metadata{}},
+		{"expresiones_tiempo", w.ExpresionesTiempo, models.TipoTiempo, models.Metadata{}},
+		{"negaciones", w.Negaciones, models.TipoNegativo, models.Metadata{}},
+		{"interrogativos", w.Interrogativos, models.TipoInterrogativo, models.Metadata{}},
+		{"preposiciones", w.Preposiciones, models.TipoPreposicion, models.Metadata{}},
+		{"articulos", w.Articulos, models.TipoArticulo, models.Metadata{}},
+		{"adjetivos.apariencia", w.Adjetivos["apariencia"], models.TipoAdjetivo, models.Metadata{}},
+		{"adjetivos.personalidad", w.Adjetivos["personalidad"], models.TipoAdjetivo, models.Metadata{}},
+		{"adjetivos.estado", w.Adjetivos["estado"], models.TipoAdjetivo, models.Metadata{}},
+		{"adverbios.tiempo", w.Adverbios["tiempo"], models.TipoAdverbio, models.Metadata{}},
+		{"adverbios.modo", w.Adverbios["modo"], models.TipoAdverbio, models.Metadata{}},
+		{"adverbios.frecuencia", w.Adverbios["frecuencia"], models.TipoAdverbio, models.Metadata{}},
+		{"complementos.objetos", w.Complementos.Objetos, models.TipoComplemento, models.Metadata{}},
+		{"complementos.lugares", w.Complementos.Lugares, models.TipoComplemento, models.Metadata{}},
+		{"complementos.comida", w.Complementos.Comida, models.TipoComplemento, models.Metadata{}},
+	}
+}
+
+// validar comprueba que las palabras formen un diccionario utilizable antes de
+// sustituir al que está en uso
+func (w WordsData) validar() error {
+	if len(w.Sujeto) == 0 {
+		return errors.New("the dictionary has no subjects")
+	}
+	if len(w.Verbos.Regulares)+len(w.Verbos.Irregulares.VerbosComunes) == 0 {
+		return errors.New("the dictionary has no verbs")
+	}
+	for _, categoria := range w.categorias() {
+		for i, palabra := range categoria.palabras {
+			if strings.TrimSpace(palabra) == "" {
+				return fmt.Errorf("%s[%d] is empty", categoria.nombre, i)
+			}
+		}
+	}
+	for i, f := range w.Verbos.Formas {
+		if f.Base == "" || f.Pasado == "" {
+			return fmt.Errorf("verbos.formas[%d] needs a base and a past form", i)
+		}
+	}
+	for contraccion, expansion := range w.Contracciones {
+		if strings.TrimSpace(expansion) == "" {
+			return fmt.Errorf("contracciones[%q] is empty", contraccion)
+		}
+	}
+	return nil
+}
+
+// CargarDiccionario carga el archivo de palabras y lo pone en uso; las
+// recargas posteriores leen el mismo archivo y las ediciones lo escriben. Si
+// falla, el diccionario en uso no cambia.
+func CargarDiccionario(ruta string) error {
+	return CargarDiccionarioFS(nil, ruta)
+}
+
+// CargarDiccionarioFS es como CargarDiccionario pero lee el archivo de fsys,
+// que no se puede editar; con fsys nil lee del disco
+func CargarDiccionarioFS(fsys fs.FS, ruta string) error {
+	recarga.Lock()
+	defer recarga.Unlock()
+
+	archivosDiccionario, rutaDiccionario, editable = fsys, ruta, fsys == nil
+	return cargarEnUso()
+}
+
+// RecargarDiccionario vuelve a leer el archivo de palabras y, si es válido,
+// sustituye al diccionario en uso. Si no lo es, devuelve el error y el
+// diccionario anterior sigue en uso.
+func RecargarDiccionario() error {
+	recarga.Lock()
+	defer recarga.Unlock()
+
+	return cargarEnUso()
+}
+
+// cargarEnUso carga rutaDiccionario y lo pone en uso si es válido; quien la
+// llama tiene bloqueado recarga
+func cargarEnUso() error {
+	d, err := cargarDesde(archivosDiccionario, rutaDiccionario)
+	errorCarga = err
+	if err != nil {
+		return err
+	}
+	actual.Store(d)
+	return nil
+}
+
+// inicializarDiccionario carga la ruta por defecto si nadie llamó antes a
+// CargarDiccionario. Si el archivo no se puede cargar se usa un diccionario
+// vacío, en el que todas las palabras son desconocidas, y EstadoDiccionario
+// informa del error.
+func inicializarDiccionario() {
+	once.Do(func() {
+		recarga.Lock()
+		defer recarga.Unlock()
+
+		if actual.Load() == nil && cargarEnUso() != nil {
+			actual.CompareAndSwap(nil, nuevoDiccionario(WordsData{}))
+		}
+	})
+}
+
+// diccionarioActual devuelve el diccionario en uso. Quien lo recibe lo puede
+// seguir leyendo aunque entretanto se recargue.
+func diccionarioActual() *Dictionary {
+	if d := actual.Load(); d != nil {
+		return d
+	}
+	inicializarDiccionario()
+	return actual.Load()
+}
+
+// EntradasDiccionario devuelve el número de palabras y expresiones del
+// diccionario en uso
+func EntradasDiccionario() int {
+	return diccionarioActual().Entradas()
+}
+
+// EstadoDiccionario informa de si hay un diccionario cargado y del resultado
+// de la última carga
+func EstadoDiccionario() models.EstadoDiccionario {
+	recarga.Lock()
+	estado := models.EstadoDiccionario{Ruta: rutaDiccionario}
+	if errorCarga != nil {
+		estado.Error = errorCarga.Error()
+	}
+	recarga.Unlock()
+
+	if d := actual.Load(); d != nil && !d.cargado.IsZero() {
+		estado.Listo = true
+		estado.Ruta = d.ruta
+		estado.Entradas = d.Entradas()
+		estado.Cargado = &d.cargado
+	}
+	return estado
+}
+
+// VigilarDiccionario comprueba cada intervalo si el archivo de palabras cambió
+// y en ese caso lo recarga; avisar recibe el resultado de cada recarga (nil si
+// tuvo éxito). Termina cuando se cancela el contexto.
+func VigilarDiccionario(ctx context.Context, intervalo time.Duration, avisar func(error)) {
+	recarga.Lock()
+	fsys, ruta := archivosDiccionario, rutaDiccionario
+	recarga.Unlock()
+
+	anterior, _ := infoArchivo(fsys, ruta)
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Mientras se reemplaza el archivo puede no existir; se vuelve a mirar
+		info, err := infoArchivo(fsys, ruta)
+		if err != nil {
+			continue
+		}
+		if anterior != nil && info.ModTime().Equal(anterior.ModTime()) && info.Size() == anterior.Size() {
+			continue
+		}
+		anterior = info
+		avisar(RecargarDiccionario())
+	}
+}
+
+// Función para cargar palabras desde el archivo JSON de fsys, o del disco si es nil
+func cargarPalabrasDesdeJSON(fsys fs.FS, filepath string) (WordsData, error) {
+	var wordsData WordsData
+	file, err := leerArchivo(fsys, filepath)
+	if err != nil {
+		return wordsData, err
+	}
+
+	err = json.Unmarshal(file, &wordsData)
+	if err != nil {
+		return wordsData, err
+	}
+
+	return wordsData, nil
+}
+
+// leerArchivo lee un archivo de fsys, o del disco si es nil
+func leerArchivo(fsys fs.FS, ruta string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile(ruta)
+	}
+	return fs.ReadFile(fsys, ruta)
+}
+
+// infoArchivo describe un archivo de fsys, o del disco si es nil
+func infoArchivo(fsys fs.FS, ruta string) (fs.FileInfo, error) {
+	if fsys == nil {
+		return os.Stat(ruta)
+	}
+	return fs.Stat(fsys, ruta)
+}
+
+// nuevoDiccionario construye una versión del diccionario con las palabras de
+// cada categoría, las formas verbales y las contracciones
+func nuevoDiccionario(wordsData WordsData) *Dictionary {
+	d := &Dictionary{
+		palabras:       make(map[string]models.Palabra, 1000),
+		longitudFrases: make(map[string]int),
+		formasVerbales: make(map[string]FormasVerbo),
+		lemas:          make(map[string]string),
+		contracciones:  make(map[string]string),
+		datos:          wordsData,
+	}
+
+	for _, categoria := range wordsData.categorias() {
+		d.agregarPalabras(categoria.palabras, categoria.tipo, categoria.metadata)
+	}
+
+	// Relacionar cada forma verbal con su lema
+	d.agregarFormasVerbales(wordsData.Verbos.Formas)
+
+	for contraccion, expansion := range wordsData.Contracciones {
+		d.contracciones[strings.ToLower(contraccion)] = expansion
+	}
+	return d
+}
+
+// normalizarClave deja una palabra o expresión como se guarda y se busca en el
+// diccionario: en minúsculas y con un solo espacio entre palabras. words.json
+// puede escribir "I" o "Cartagena"; las búsquedas no distinguen mayúsculas.
+func normalizarClave(palabra string) string {
+	return strings.ToLower(strings.Join(strings.Fields(palabra), " "))
+}
+
+// agregarPalabras agrega palabras al diccionario con su tipo y metadata
+func (d *Dictionary) agregarPalabras(palabras []string, tipo models.TipoPalabra, metadata models.Metadata) {
+	for _, palabra := range palabras {
+		clave := normalizarClave(palabra)
+		d.palabras[clave] = models.Palabra{
+			Tipo:     tipo,
+			Texto:    clave,
+			Metadata: metadata,
+		}
+
+		// Registrar las expresiones de varias palabras para el análisis léxico
+		if partes := strings.Fields(clave); len(partes) > 1 {
+			inicial := partes[0]
+			if len(partes) > d.longitudFrases[inicial] {
+				d.longitudFrases[inicial] = len(partes)
+			}
+		}
+	}
+}
+
+// agregarFormasVerbales registra las inflexiones de cada verbo y completa la
+// metadata de los pasados que ya están en el diccionario
+func (d *Dictionary) agregarFormasVerbales(formas []FormasVerbo) {
+	for _, f := range formas {
+		f = f.normalizada()
+		d.formasVerbales[f.Base] = f
+
+		for _, forma := range f.formas() {
+			if _, existe := d.lemas[forma.Texto]; !existe && forma.Texto != "" {
+				d.lemas[forma.Texto] = f.Base
+			}
+		}
+
+		if palabra, existe := d.palabras[f.Pasado]; existe && esTipoVerbal(palabra.Tipo) {
+			palabra.Metadata.Lema = f.Base
+			palabra.Metadata.Forma = models.FormaPasado
+			d.palabras[f.Pasado] = palabra
+		}
+
+		// Las formas de presente y el gerundio se reconocen como verbos para
+		// poder señalar el tiempo verbal incorrecto; las entradas existentes ganan
+		for _, forma := range []struct {
+			Forma models.FormaVerbal
+			Texto string
+		}{
+			{models.FormaBase, f.Base},
+			{models.FormaTerceraPersona, f.TerceraPersona},
+			{models.FormaGerundio, f.Gerundio},
+		} {
+			if _, existe := d.palabras[forma.Texto]; existe || forma.Texto == "" {
+				continue
+			}
+			d.palabras[forma.Texto] = models.Palabra{
+				Tipo:     models.TipoVerboSimple,
+				Texto:    forma.Texto,
+				Metadata: models.Metadata{Lema: f.Base, Forma: forma.Forma},
+			}
+		}
+	}
+}
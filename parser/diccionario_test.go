@@ -0,0 +1,223 @@
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+	"validar_oraciones/models"
+)
+
+// diccionarioTemporal copia words.json a un directorio temporal, apunta el
+// diccionario a la copia y lo restaura al terminar la prueba
+func diccionarioTemporal(t *testing.T) (string, WordsData) {
+	t.Helper()
+	// Se copia el archivo tal cual para conservar todo su contenido
+	contenido, err := leerArchivo(archivosDiccionario, rutaDiccionario)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wordsData WordsData
+	if err := json.Unmarshal(contenido, &wordsData); err != nil {
+		t.Fatal(err)
+	}
+
+	ruta := filepath.Join(t.TempDir(), "words.json")
+	if err := os.WriteFile(ruta, contenido, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	original, archivos, eraEditable := rutaDiccionario, archivosDiccionario, editable
+	rutaDiccionario, archivosDiccionario, editable = ruta, nil, true
+	t.Cleanup(func() {
+		rutaDiccionario, archivosDiccionario, editable = original, archivos, eraEditable
+		if err := RecargarDiccionario(); err != nil {
+			t.Errorf("restoring the dictionary: %v", err)
+		}
+	})
+	return ruta, wordsData
+}
+
+func escribirPalabras(t *testing.T, ruta string, wordsData WordsData) {
+	t.Helper()
+	contenido, err := json.Marshal(wordsData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ruta, contenido, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecargarDiccionario(t *testing.T) {
+	ruta, wordsData := diccionarioTemporal(t)
+
+	wordsData.Sujeto = append(wordsData.Sujeto, "grandpa")
+	escribirPalabras(t, ruta, wordsData)
+	if err := RecargarDiccionario(); err != nil {
+		t.Fatalf("RecargarDiccionario() unexpected error = %v", err)
+	}
+	if _, existe := BuscarPalabra("grandpa"); !existe {
+		t.Fatal("the new word is not in the reloaded dictionary")
+	}
+
+	invalidos := []struct {
+		name      string
+		modificar func(w *WordsData) []byte
+	}{
+		{"invalid JSON", func(*WordsData) []byte { return []byte(`{"sujeto":`) }},
+		{"no subjects", func(w *WordsData) []byte { w.Sujeto = nil; return nil }},
+		{"empty word", func(w *WordsData) []byte { w.Preposiciones = append(w.Preposiciones, " "); return nil }},
+		{"verb form without past", func(w *WordsData) []byte {
+			w.Verbos.Formas = append(w.Verbos.Formas, FormasVerbo{Base: "zoom"})
+			return nil
+		}},
+	}
+	for _, tt := range invalidos {
+		t.Run(tt.name, func(t *testing.T) {
+			copia := wordsData
+			if contenido := tt.modificar(&copia); contenido != nil {
+				if err := os.WriteFile(ruta, contenido, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				escribirPalabras(t, ruta, copia)
+			}
+
+			if err := RecargarDiccionario(); err == nil {
+				t.Fatal("RecargarDiccionario() expected an error")
+			}
+			if _, existe := BuscarPalabra("grandpa"); !existe {
+				t.Error("a failed reload replaced the dictionary in use")
+			}
+		})
+	}
+}
+
+func TestVigilarDiccionario(t *testing.T) {
+	ruta, wordsData := diccionarioTemporal(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recargas := make(chan error, 10)
+	go VigilarDiccionario(ctx, 10*time.Millisecond, func(err error) { recargas <- err })
+
+	// Sin cambios en el archivo no se recarga nada
+	select {
+	case err := <-recargas:
+		t.Fatalf("reloaded an unchanged file: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	wordsData.Sujeto = append(wordsData.Sujeto, "grandma")
+	escribirPalabras(t, ruta, wordsData)
+	select {
+	case err := <-recargas:
+		if err != nil {
+			t.Fatalf("reload error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the changed file was not reloaded")
+	}
+	if _, existe := BuscarPalabra("grandma"); !existe {
+		t.Error("the new word is not in the reloaded dictionary")
+	}
+}
+
+// TestBuscarPalabraMayusculas tests that entries written with capitals in
+// words.json are found regardless of case
+func TestBuscarPalabraMayusculas(t *testing.T) {
+	tests := []struct {
+		palabra string
+		tipo    models.TipoPalabra
+	}{
+		{"I", models.TipoSujeto},
+		{"i", models.TipoSujeto},
+		{"John", models.TipoSujeto},
+		{"Cartagena", models.TipoComplemento},
+		{"CARTAGENA", models.TipoComplemento},
+	}
+	for _, tt := range tests {
+		if palabra, existe := BuscarPalabra(tt.palabra); !existe || palabra.Tipo != tt.tipo {
+			t.Errorf("BuscarPalabra(%q) = %v, %v, expected %v", tt.palabra, palabra.Tipo, existe, tt.tipo)
+		}
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	dir := t.TempDir()
+	malformado := filepath.Join(dir, "malformed.json")
+	if err := os.WriteFile(malformado, []byte(`{"sujeto": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ruta := range []string{filepath.Join(dir, "missing.json"), malformado} {
+		if d, err := LoadDictionary(ruta); err == nil || d != nil {
+			t.Errorf("LoadDictionary(%s) = %v, %v, expected an error", ruta, d, err)
+		}
+	}
+
+	antes := diccionarioActual()
+	d, err := LoadDictionary(rutaDiccionario)
+	if err != nil {
+		t.Fatalf("LoadDictionary() unexpected error = %v", err)
+	}
+	if d.Entradas() == 0 {
+		t.Error("the loaded dictionary is empty")
+	}
+	if diccionarioActual() != antes {
+		t.Error("LoadDictionary replaced the dictionary in use")
+	}
+}
+
+func TestCargarDiccionarioFS(t *testing.T) {
+	_, wordsData := diccionarioTemporal(t)
+	wordsData.Sujeto = append(wordsData.Sujeto, "grandson")
+	contenido, err := json.Marshal(wordsData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivos := fstest.MapFS{"data/words.json": {Data: contenido}}
+
+	if _, err := LoadDictionaryFS(archivos, "data/missing.json"); err == nil {
+		t.Error("LoadDictionaryFS() expected an error for a missing file")
+	}
+	d, err := LoadDictionaryFS(archivos, "data/words.json")
+	if err != nil {
+		t.Fatalf("LoadDictionaryFS() unexpected error = %v", err)
+	}
+	if _, existe := d.BuscarPalabra("grandson"); !existe {
+		t.Error("the dictionary loaded from the FS is missing its words")
+	}
+
+	if err := CargarDiccionarioFS(archivos, "data/words.json"); err != nil {
+		t.Fatalf("CargarDiccionarioFS() unexpected error = %v", err)
+	}
+	// Las recargas leen del mismo sistema de archivos, no del disco
+	if err := RecargarDiccionario(); err != nil {
+		t.Fatalf("RecargarDiccionario() unexpected error = %v", err)
+	}
+	if _, existe := BuscarPalabra("grandson"); !existe {
+		t.Error("the package dictionary was not loaded from the FS")
+	}
+}
+
+func TestEstadoDiccionario(t *testing.T) {
+	ruta, _ := diccionarioTemporal(t)
+	if err := RecargarDiccionario(); err != nil {
+		t.Fatal(err)
+	}
+	if estado := EstadoDiccionario(); !estado.Listo || estado.Ruta != ruta || estado.Entradas == 0 || estado.Error != "" {
+		t.Errorf("EstadoDiccionario() = %+v, expected a loaded dictionary from %s", estado, ruta)
+	}
+
+	if err := os.WriteFile(ruta, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	RecargarDiccionario()
+	if estado := EstadoDiccionario(); !estado.Listo || estado.Error == "" {
+		t.Errorf("EstadoDiccionario() = %+v, expected the previous dictionary and the reload error", estado)
+	}
+}
@@ -0,0 +1,345 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"validar_oraciones/models"
+)
+
+// Errores de la edición del diccionario
+var (
+	ErrCategoriaDesconocida = errors.New("unknown category")
+	ErrPalabraExistente     = errors.New("the word is already in the category")
+	ErrPalabraNoEncontrada  = errors.New("the word is not in the category")
+	ErrDiccionarioInvalido  = errors.New("the change would leave the dictionary invalid")
+	ErrSoloLectura          = errors.New("the dictionary was not loaded from a file on disk and cannot be edited")
+)
+
+// Categorias devuelve cada lista de palabras del diccionario con su nombre en
+// el JSON, en el orden de WordsData
+func (d *Dictionary) Categorias() []models.CategoriaDiccionario {
+	var resultado []models.CategoriaDiccionario
+	for _, categoria := range d.datos.categorias() {
+		// adjetivos.estado aparece dos veces: es la misma lista
+		if slices.ContainsFunc(resultado, func(c models.CategoriaDiccionario) bool { return c.Categoria == categoria.nombre }) {
+			continue
+		}
+		resultado = append(resultado, models.CategoriaDiccionario{
+			Categoria: categoria.nombre,
+			Palabras:  append([]string{}, categoria.palabras...),
+		})
+	}
+	return resultado
+}
+
+// PalabrasDeCategoria devuelve las palabras de una categoría
+func (d *Dictionary) PalabrasDeCategoria(nombre string) ([]string, error) {
+	palabras, err := d.datos.palabrasDe(nombre)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, palabras...), nil
+}
+
+// BuscarEnCategorias devuelve las palabras que contienen la consulta, sin
+// distinguir mayúsculas, junto con su categoría
+func (d *Dictionary) BuscarEnCategorias(consulta string) []models.EntradaCategoria {
+	consulta = strings.ToLower(strings.TrimSpace(consulta))
+	resultado := []models.EntradaCategoria{}
+	for _, categoria := range d.Categorias() {
+		for _, palabra := range categoria.Palabras {
+			if strings.Contains(strings.ToLower(palabra), consulta) {
+				resultado = append(resultado, models.EntradaCategoria{Palabra: palabra, Categoria: categoria.Categoria})
+			}
+		}
+	}
+	return resultado
+}
+
+// CategoriasDiccionario devuelve las listas de palabras del diccionario en uso
+func CategoriasDiccionario() []models.CategoriaDiccionario {
+	return diccionarioActual().Categorias()
+}
+
+// PalabrasDeCategoria devuelve las palabras de una categoría del diccionario en uso
+func PalabrasDeCategoria(nombre string) ([]string, error) {
+	return diccionarioActual().PalabrasDeCategoria(nombre)
+}
+
+// BuscarEnCategorias busca palabras en las categorías del diccionario en uso
+func BuscarEnCategorias(consulta string) []models.EntradaCategoria {
+	return diccionarioActual().BuscarEnCategorias(consulta)
+}
+
+// LimpiarPalabra quita los espacios sobrantes de una palabra o expresión. Las
+// mayúsculas se conservan tal como se escriben en el archivo ("Cartagena"); el
+// diccionario no las distingue al buscar.
+func LimpiarPalabra(palabra string) string {
+	return strings.Join(strings.Fields(palabra), " ")
+}
+
+// AgregarPalabra agrega una palabra a una categoría, guarda el archivo de
+// palabras y pone en uso el diccionario resultante
+func AgregarPalabra(categoria, palabra string) error {
+	palabra = LimpiarPalabra(palabra)
+	return editarDiccionario(func(w *WordsData) error {
+		palabras, err := w.palabrasDe(categoria)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(palabras, igualA(palabra)) {
+			return fmt.Errorf("%w: %q in %s", ErrPalabraExistente, palabra, categoria)
+		}
+		w.asignar(categoria, append(palabras, palabra))
+		return nil
+	})
+}
+
+// CambiarPalabra corrige una palabra, la mueve a otra categoría o ambas cosas;
+// la guarda y pone en uso el diccionario resultante
+func CambiarPalabra(categoria, palabra, nuevaCategoria, nuevaPalabra string) error {
+	nuevaPalabra = LimpiarPalabra(nuevaPalabra)
+	return editarDiccionario(func(w *WordsData) error {
+		origen, err := w.palabrasDe(categoria)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(origen, igualA(palabra))
+		if i < 0 {
+			return fmt.Errorf("%w: %q in %s", ErrPalabraNoEncontrada, palabra, categoria)
+		}
+		destino, err := w.palabrasDe(nuevaCategoria)
+		if err != nil {
+			return err
+		}
+		// En la misma categoría se puede cambiar solo la escritura ("Cartagena" por "cartagena")
+		for j, otra := range destino {
+			if igualA(nuevaPalabra)(otra) && (nuevaCategoria != categoria || j != i) {
+				return fmt.Errorf("%w: %q in %s", ErrPalabraExistente, nuevaPalabra, nuevaCategoria)
+			}
+		}
+
+		if nuevaCategoria == categoria {
+			// Se conserva la posición de la palabra en la lista
+			origen[i] = nuevaPalabra
+			return nil
+		}
+		w.asignar(categoria, slices.Delete(origen, i, i+1))
+		w.asignar(nuevaCategoria, append(destino, nuevaPalabra))
+		return nil
+	})
+}
+
+// EliminarPalabra quita una palabra de una categoría, guarda el archivo de
+// palabras y pone en uso el diccionario resultante
+func EliminarPalabra(categoria, palabra string) error {
+	return editarDiccionario(func(w *WordsData) error {
+		palabras, err := w.palabrasDe(categoria)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(palabras, igualA(palabra))
+		if i < 0 {
+			return fmt.Errorf("%w: %q in %s", ErrPalabraNoEncontrada, palabra, categoria)
+		}
+		w.asignar(categoria, slices.Delete(palabras, i, i+1))
+		return nil
+	})
+}
+
+// igualA compara palabras con la misma normalización que las búsquedas del
+// diccionario
+func igualA(palabra string) func(string) bool {
+	clave := normalizarClave(palabra)
+	return func(otra string) bool {
+		return normalizarClave(otra) == clave
+	}
+}
+
+// editarDiccionario lee el archivo de palabras en uso, le aplica el cambio,
+// valida el resultado, lo guarda y lo pone en uso. Se lee el archivo y no el
+// diccionario en memoria para no perder cambios hechos a mano que aún no se
+// hayan recargado. Si algo falla, ni el archivo ni el diccionario cambian.
+func editarDiccionario(cambiar func(w *WordsData) error) error {
+	recarga.Lock()
+	defer recarga.Unlock()
+
+	if !editable {
+		return ErrSoloLectura
+	}
+	contenido, err := leerArchivo(nil, rutaDiccionario)
+	if err != nil {
+		return err
+	}
+	var antes, despues WordsData
+	if err := json.Unmarshal(contenido, &antes); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(contenido, &despues); err != nil {
+		return err
+	}
+	if err := cambiar(&despues); err != nil {
+		return err
+	}
+	d, err := NewDictionary(despues)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDiccionarioInvalido, err)
+	}
+	if err := guardarPalabras(rutaDiccionario, contenido, antes, despues); err != nil {
+		return err
+	}
+
+	d.ruta = rutaDiccionario
+	errorCarga = nil
+	actual.Store(d)
+	return nil
+}
+
+// guardarPalabras guarda en el archivo de palabras las categorías que cambiaron
+// entre antes y despues. El resto del contenido, incluidas las claves que
+// WordsData no conoce, se conserva; el archivo se reescribe con las claves en
+// orden alfabético, dos espacios de sangría y una palabra por línea.
+//
+// Se escribe en un temporal del mismo directorio y se renombra, así ni el
+// servidor ni VigilarDiccionario leen nunca un archivo a medio escribir.
+func guardarPalabras(ruta string, original []byte, antes, despues WordsData) error {
+	decoder := json.NewDecoder(bytes.NewReader(original))
+	decoder.UseNumber() // Los números que no se leen se guardan tal cual
+	var arbol map[string]any
+	if err := decoder.Decode(&arbol); err != nil {
+		return err
+	}
+	for _, categoria := range despues.categorias() {
+		anteriores, _ := antes.palabrasDe(categoria.nombre)
+		if !slices.Equal(anteriores, categoria.palabras) {
+			if err := asignarEnArbol(arbol, strings.Split(categoria.nombre, "."), categoria.palabras); err != nil {
+				return err
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(arbol); err != nil {
+		return err
+	}
+
+	permisos := fs.FileMode(0o644)
+	if info, err := os.Stat(ruta); err == nil {
+		permisos = info.Mode().Perm()
+	}
+
+	temporal, err := os.CreateTemp(filepath.Dir(ruta), "."+filepath.Base(ruta)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporal.Name()) // Después de renombrarlo ya no existe
+
+	if _, err := temporal.Write(buffer.Bytes()); err != nil {
+		temporal.Close()
+		return err
+	}
+	if err := temporal.Chmod(permisos); err != nil {
+		temporal.Close()
+		return err
+	}
+	if err := temporal.Sync(); err != nil {
+		temporal.Close()
+		return err
+	}
+	if err := temporal.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporal.Name(), ruta)
+}
+
+// asignarEnArbol pone la lista en la ruta de claves del JSON decodificado,
+// creando los objetos que falten ("articulos" no existe en words.json)
+func asignarEnArbol(arbol map[string]any, ruta []string, palabras []string) error {
+	for _, clave := range ruta[:len(ruta)-1] {
+		hijo, existe := arbol[clave]
+		if !existe {
+			hijo = make(map[string]any)
+			arbol[clave] = hijo
+		}
+		objeto, esObjeto := hijo.(map[string]any)
+		if !esObjeto {
+			return fmt.Errorf("%s is not an object in the file", strings.Join(ruta, "."))
+		}
+		arbol = objeto
+	}
+	if palabras == nil {
+		palabras = []string{}
+	}
+	arbol[ruta[len(ruta)-1]] = palabras
+	return nil
+}
+
+// palabrasDe devuelve la lista de palabras de una categoría
+func (w *WordsData) palabrasDe(nombre string) ([]string, error) {
+	for _, categoria := range w.categorias() {
+		if categoria.nombre == nombre {
+			return categoria.palabras, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrCategoriaDesconocida, nombre)
+}
+
+// asignar sustituye la lista de palabras de una de las categorías de
+// categorias(); quien la llama ya comprobó el nombre con palabrasDe
+func (w *WordsData) asignar(nombre string, palabras []string) {
+	grupo, lista, _ := strings.Cut(nombre, ".")
+	switch grupo {
+	case "sujeto":
+		w.Sujeto = palabras
+	case "modales_pasados":
+		w.ModalesPasados = palabras
+	case "expresiones_tiempo":
+		w.ExpresionesTiempo = palabras
+	case "negaciones":
+		w.Negaciones = palabras
+	case "interrogativos":
+		w.Interrogativos = palabras
+	case "preposiciones":
+		w.Preposiciones = palabras
+	case "articulos":
+		w.Articulos = palabras
+	case "adjetivos":
+		if w.Adjetivos == nil {
+			w.Adjetivos = make(map[string][]string)
+		}
+		w.Adjetivos[lista] = palabras
+	case "adverbios":
+		if w.Adverbios == nil {
+			w.Adverbios = make(map[string][]string)
+		}
+		w.Adverbios[lista] = palabras
+	case "complementos":
+		switch lista {
+		case "objetos":
+			w.Complementos.Objetos = palabras
+		case "lugares":
+			w.Complementos.Lugares = palabras
+		case "comida":
+			w.Complementos.Comida = palabras
+		}
+	case "verbos":
+		switch nombre {
+		case "verbos.regulares":
+			w.Verbos.Regulares = palabras
+		case "verbos.irregulares.verbos_comunes":
+			w.Verbos.Irregulares.VerbosComunes = palabras
+		case "verbos.irregulares.verbos_auxiliares":
+			w.Verbos.Irregulares.Auxiliares = palabras
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package validators
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"validar_oraciones/models"
+)
+
+// TestAsignarCategorias tests that every category listed by categorias() can be replaced
+func TestAsignarCategorias(t *testing.T) {
+	var w WordsData
+	for _, categoria := range w.categorias() {
+		w.asignar(categoria.nombre, []string{"x"})
+		if palabras, err := w.palabrasDe(categoria.nombre); err != nil || !slices.Equal(palabras, []string{"x"}) {
+			t.Errorf("%s = %v, %v after asignar", categoria.nombre, palabras, err)
+		}
+	}
+	if _, err := w.palabrasDe("verbos.formas"); !errors.Is(err, ErrCategoriaDesconocida) {
+		t.Errorf("palabrasDe(verbos.formas) error = %v, expected ErrCategoriaDesconocida", err)
+	}
+}
+
+func TestEditarDiccionario(t *testing.T) {
+	ruta, original := diccionarioTemporal(t)
+	if err := RecargarDiccionario(); err != nil {
+		t.Fatal(err)
+	}
+
+	// enArchivo indica si la palabra está guardada en la categoría
+	enArchivo := func(t *testing.T, categoria, palabra string) bool {
+		t.Helper()
+		wordsData, err := cargarPalabrasDesdeJSON(nil, ruta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		palabras, err := wordsData.palabrasDe(categoria)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return slices.Contains(palabras, palabra)
+	}
+
+	t.Run("add", func(t *testing.T) {
+		if err := AgregarPalabra("complementos.lugares", "  New   York "); err != nil {
+			t.Fatalf("AgregarPalabra() unexpected error = %v", err)
+		}
+		if !enArchivo(t, "complementos.lugares", "New York") {
+			t.Error("the new word was not saved as written")
+		}
+		if _, existe := BuscarPalabra("New York"); !existe {
+			t.Error("the new word is not in the dictionary in use")
+		}
+	})
+
+	t.Run("update and move", func(t *testing.T) {
+		if err := CambiarPalabra("complementos.lugares", "new york", "sujeto", "New Yorkers"); err != nil {
+			t.Fatalf("CambiarPalabra() unexpected error = %v", err)
+		}
+		if enArchivo(t, "complementos.lugares", "New York") || !enArchivo(t, "sujeto", "New Yorkers") {
+			t.Error("the word was not moved in the saved file")
+		}
+		if palabra, _ := BuscarPalabra("new yorkers"); palabra.Tipo != models.TipoSujeto {
+			t.Errorf("new yorkers = %v, expected a subject", palabra.Tipo)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := EliminarPalabra("sujeto", "New Yorkers"); err != nil {
+			t.Fatalf("EliminarPalabra() unexpected error = %v", err)
+		}
+		if _, existe := BuscarPalabra("new yorkers"); existe || enArchivo(t, "sujeto", "New Yorkers") {
+			t.Error("the deleted word is still in the dictionary")
+		}
+	})
+
+	t.Run("case only change", func(t *testing.T) {
+		if err := AgregarPalabra("complementos.lugares", "Springfield"); err != nil {
+			t.Fatal(err)
+		}
+		if err := CambiarPalabra("complementos.lugares", "springfield", "complementos.lugares", "SPRINGFIELD"); err != nil {
+			t.Fatalf("CambiarPalabra() unexpected error = %v", err)
+		}
+		if !enArchivo(t, "complementos.lugares", "SPRINGFIELD") {
+			t.Error("the new spelling was not saved")
+		}
+		if err := EliminarPalabra("complementos.lugares", "Springfield"); err != nil {
+			t.Fatalf("EliminarPalabra() unexpected error = %v", err)
+		}
+	})
+
+	errores := []struct {
+		name     string
+		editar   func() error
+		esperado error
+	}{
+		{"duplicate", func() error { return AgregarPalabra("sujeto", original.Sujeto[0]) }, ErrPalabraExistente},
+		{"duplicate in another case", func() error { return AgregarPalabra("sujeto", strings.ToUpper(original.Sujeto[0])) }, ErrPalabraExistente},
+		{"unknown category", func() error { return AgregarPalabra("verbos.formas", "zoom") }, ErrCategoriaDesconocida},
+		{"missing word", func() error { return EliminarPalabra("sujeto", "nobody at all") }, ErrPalabraNoEncontrada},
+		{"move onto an existing word", func() error {
+			return CambiarPalabra("sujeto", original.Sujeto[0], "sujeto", original.Sujeto[1])
+		}, ErrPalabraExistente},
+		{"invalid result", func() error {
+			var err error
+			for _, sujeto := range original.Sujeto {
+				if err = EliminarPalabra("sujeto", sujeto); err != nil {
+					break
+				}
+			}
+			return err
+		}, ErrDiccionarioInvalido},
+	}
+	for _, tt := range errores {
+		t.Run(tt.name, func(t *testing.T) {
+			antes, err := os.ReadFile(ruta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.editar(); !errors.Is(err, tt.esperado) {
+				t.Fatalf("error = %v, expected %v", err, tt.esperado)
+			}
+			if tt.esperado == ErrDiccionarioInvalido {
+				return // Las eliminaciones anteriores sí se guardaron
+			}
+			if despues, _ := os.ReadFile(ruta); string(despues) != string(antes) {
+				t.Error("a failed edit changed the file")
+			}
+		})
+	}
+
+	t.Run("fields the dictionary does not use are kept", func(t *testing.T) {
+		wordsData, err := cargarPalabrasDesdeJSON(nil, ruta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(wordsData.Verbos.Irregulares.VerbosEstado) == 0 || len(wordsData.Verbos.Formas) != len(original.Verbos.Formas) {
+			t.Error("saving the dictionary dropped data")
+		}
+	})
+
+	t.Run("embedded dictionary", func(t *testing.T) {
+		contenido, err := os.ReadFile(ruta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := CargarDiccionarioFS(fstest.MapFS{"words.json": {Data: contenido}}, "words.json"); err != nil {
+			t.Fatal(err)
+		}
+		if err := AgregarPalabra("sujeto", "grandpa"); !errors.Is(err, ErrSoloLectura) {
+			t.Errorf("error = %v, expected ErrSoloLectura", err)
+		}
+	})
+}
+
+// TestGuardarPalabrasConservaContenido tests that saving keeps the keys
+// WordsData does not model and adds missing categories
+func TestGuardarPalabrasConservaContenido(t *testing.T) {
+	ruta, wordsData := diccionarioTemporal(t)
+
+	// El archivo gana claves desconocidas en varios niveles y pierde "articulos"
+	var arbol map[string]any
+	contenido, err := os.ReadFile(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(contenido, &arbol); err != nil {
+		t.Fatal(err)
+	}
+	delete(arbol, "articulos")
+	arbol["version"] = 3
+	arbol["notas"] = map[string]any{"autor": "teacher"}
+	arbol["complementos"].(map[string]any)["colores"] = []string{"red"}
+	if contenido, err = json.Marshal(arbol); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ruta, contenido, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AgregarPalabra("articulos", "the"); err != nil {
+		t.Fatalf("AgregarPalabra() unexpected error = %v", err)
+	}
+	if err := AgregarPalabra("complementos.lugares", "Springfield"); err != nil {
+		t.Fatalf("AgregarPalabra() unexpected error = %v", err)
+	}
+
+	contenido, err = os.ReadFile(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var guardado struct {
+		Version      int                 `json:"version"`
+		Notas        map[string]string   `json:"notas"`
+		Articulos    []string            `json:"articulos"`
+		Complementos map[string][]string `json:"complementos"`
+	}
+	if err := json.Unmarshal(contenido, &guardado); err != nil {
+		t.Fatal(err)
+	}
+	if guardado.Version != 3 || guardado.Notas["autor"] != "teacher" || !slices.Equal(guardado.Complementos["colores"], []string{"red"}) {
+		t.Errorf("unknown keys were not kept: %+v", guardado)
+	}
+	if !slices.Equal(guardado.Articulos, []string{"the"}) {
+		t.Errorf("articulos = %v, expected [the]", guardado.Articulos)
+	}
+	if lugares := guardado.Complementos["lugares"]; len(lugares) != len(wordsData.Complementos.Lugares)+1 || lugares[len(lugares)-1] != "Springfield" {
+		t.Errorf("lugares = %v, expected Springfield at the end", lugares)
+	}
+
+	// Las categorías que no cambiaron quedan igual
+	var despues WordsData
+	if err := json.Unmarshal(contenido, &despues); err != nil {
+		t.Fatal(err)
+	}
+	despues.Articulos, despues.Complementos.Lugares = wordsData.Articulos, wordsData.Complementos.Lugares
+	if !reflect.DeepEqual(despues, wordsData) {
+		t.Error("saving changed categories that were not edited")
+	}
+}
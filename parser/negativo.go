@@ -0,0 +1,139 @@
+package validators
+
+import (
+	"fmt"
+	"validar_oraciones/models"
+)
+
+// diagnosticarNegativo aplica las reglas del pasado simple negativo:
+// sujeto + did not/didn't + verbo en forma base + complemento, o bien
+// sujeto + was not/wasn't (were not/weren't) + complemento
+func diagnosticarNegativo(tokens []models.Token, v *validacion) []models.Diagnostic {
+	if len(tokens) == 0 {
+		v.reportar(nuevoDiagnostico(ReglaSinTokens, "No tokens found.", tokens, 0, 0))
+		return v.diagnosticos
+	}
+
+	sujeto, negacion := -1, -1
+	ser := false // Negación de "to be": la negación empieza en was/were
+	for i, token := range tokens {
+		switch {
+		case token.Tipo == models.TipoSujeto && sujeto == -1:
+			sujeto = i
+		case token.Tipo == models.TipoNegativo && negacion == -1:
+			negacion = i
+		case token.Texto == "not" && i > 0 && esWasWere(tokens[i-1]) && negacion == -1:
+			negacion, ser = i-1, true
+		}
+
+		// "did" on its own is reported as a missing negation below
+		if auxiliaresNoPermitidos[token.Texto] && token.Texto != "did" {
+			if v.reportar(nuevoDiagnostico(ReglaAuxiliar,
+				"The sentence should not contain auxiliary verbs other than 'did'.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+		}
+	}
+
+	if sujeto == -1 {
+		if v.reportar(nuevoDiagnostico(ReglaSujetoFaltante,
+			"The subject is missing in the sentence.", tokens, 0, len(tokens))) {
+			return v.diagnosticos
+		}
+	}
+
+	if negacion == -1 {
+		v.reportar(nuevoDiagnostico(ReglaNegacionFaltante,
+			"A negative sentence needs 'did not' or 'didn't' before the verb.", tokens, 0, len(tokens)))
+		return v.diagnosticos
+	}
+
+	if sujeto != -1 {
+		if sujeto > negacion {
+			if v.reportar(nuevoDiagnostico(ReglaOrdenSujetoVerbo,
+				fmt.Sprintf("The subject must come before '%s'.", textoToken(tokens[negacion])), tokens, sujeto, sujeto+1)) {
+				return v.diagnosticos
+			}
+		} else {
+			for i := sujeto + 1; i < negacion; i++ {
+				if tokens[i].Tipo != models.TipoAdverbio {
+					if v.reportar(nuevoDiagnostico(ReglaVerboTrasSujeto,
+						fmt.Sprintf("'%s' must immediately follow the subject.", textoToken(tokens[negacion])), tokens, i, i+1)) {
+						return v.diagnosticos
+					}
+					break
+				}
+			}
+		}
+	}
+
+	// Ensure complements come after the verb
+	for i := 0; i < negacion; i++ {
+		if tokens[i].Tipo == models.TipoComplemento {
+			if v.reportar(nuevoDiagnostico(ReglaComplementoAntesDelVerbo,
+				"The complement must come after the verb.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+			break
+		}
+	}
+
+	// "was not" and "were not" take a complement instead of a verb and agree with the subject
+	if ser {
+		if sujeto == -1 {
+			return v.diagnosticos
+		}
+		if esperado := formaSer(tokens[sujeto].Texto, true); tokens[negacion].Texto != esperado {
+			v.reportar(diagnosticoFormaSer(tokens, negacion, ReglaConcordanciaWasWere,
+				fmt.Sprintf("Incorrect verb form for '%s'. Use '%s'.", nombreSujeto(textoToken(tokens[sujeto])), esperado), esperado))
+		}
+		return v.diagnosticos
+	}
+
+	// The verb follows the negation, optionally after an adverb ("didn't really like")
+	verbo := negacion + 1
+	for verbo < len(tokens) && tokens[verbo].Tipo == models.TipoAdverbio {
+		verbo++
+	}
+	if verbo == len(tokens) || !esVerbo(tokens[verbo]) {
+		v.reportar(nuevoDiagnostico(ReglaVerboBaseFaltante,
+			fmt.Sprintf("A verb in its base form is missing after '%s'.", textoToken(tokens[negacion])),
+			tokens, negacion, negacion+1))
+		return v.diagnosticos
+	}
+
+	if diagnostico, existe := diagnosticoVerboBase(v.diccionario, tokens, negacion, verbo); existe {
+		v.reportar(diagnostico)
+	}
+
+	return v.diagnosticos
+}
+
+// diagnosticoVerboBase comprueba que el verbo que sigue al auxiliar "did" esté en
+// forma base ("didn't go", no "didn't went") y sugiere la forma correcta
+func diagnosticoVerboBase(d *Dictionary, tokens []models.Token, auxiliar, verbo int) (models.Diagnostic, bool) {
+	token := tokens[verbo]
+	if token.Metadata.Forma == models.FormaBase {
+		return models.Diagnostic{}, false
+	}
+
+	base := token.Metadata.Lema
+	if pasado, existe := d.formaSobrerregularizada(token.Texto); existe {
+		base, _ = d.Lemma(pasado)
+	}
+	if base == token.Texto {
+		return models.Diagnostic{}, false // "read", "cut", "put"
+	}
+
+	if base == "" {
+		return nuevoDiagnostico(ReglaVerboBase,
+			fmt.Sprintf("After '%s' the verb must be in its base form.", tokens[auxiliar].Texto),
+			tokens, verbo, verbo+1), true
+	}
+
+	diagnostico := nuevoDiagnostico(ReglaVerboBase,
+		fmt.Sprintf("After '%s' use the base form '%s', not '%s'.", tokens[auxiliar].Texto, base, token.Texto),
+		tokens, verbo, verbo+1)
+	diagnostico.Sugerencia = base
+	return diagnostico, true
+}
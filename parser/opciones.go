@@ -0,0 +1,45 @@
+package validators
+
+// Opcion ajusta el comportamiento de la validación
+type Opcion func(*opciones)
+
+// opciones reúne la configuración de una validación
+type opciones struct {
+	todosLosErrores    bool
+	puntuacionEstricta bool
+	perfil             Perfil
+}
+
+// ConTodosLosErrores ejecuta todas las reglas en lugar de detenerse en el primer error
+func ConTodosLosErrores() Opcion {
+	return func(o *opciones) {
+		o.todosLosErrores = true
+	}
+}
+
+// ConPuntuacionEstricta exige mayúscula inicial, signo final y puntuación bien colocada
+func ConPuntuacionEstricta() Opcion {
+	return func(o *opciones) {
+		o.puntuacionEstricta = true
+	}
+}
+
+// ConPerfil valida la oración con las reglas del perfil de ejercicio indicado
+// (pasado simple afirmativo por defecto)
+func ConPerfil(perfil Perfil) Opcion {
+	return func(o *opciones) {
+		o.perfil = perfil
+	}
+}
+
+// aplicarOpciones construye la configuración a partir de las opciones recibidas
+func aplicarOpciones(lista []Opcion) opciones {
+	o := opciones{perfil: PerfilPorDefecto()}
+	for _, opcion := range lista {
+		opcion(&o)
+	}
+	if o.perfil.reglas == nil {
+		o.perfil = PerfilPorDefecto()
+	}
+	return o
+}
@@ -1,132 +1,217 @@
 package validators
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
-	"os"
 	"strings"
-	"sync"
 	"unicode"
+	"unicode/utf8"
 	"validar_oraciones/models"
 )
 
-// Variables globales
-var (
-	diccionario map[string]models.Palabra
-	once        sync.Once
-	mu          sync.RWMutex
-)
+// Signos que cierran una oración
+const terminadores = ".!?"
+
+// buscar devuelve la entrada del diccionario para el texto
+func (d *Dictionary) buscar(texto string) (models.Palabra, bool) {
+	palabra, existe := d.palabras[texto]
+	return palabra, existe
+}
+
+// existe indica si el texto es una entrada del diccionario
+func (d *Dictionary) existe(texto string) bool {
+	_, existe := d.palabras[texto]
+	return existe
+}
 
-// Estructura para leer el JSON de palabras
-type WordsData struct {
-	Verbos struct {
-		Regulares   []string `json:"regulares"`
-		Irregulares struct {
-			VerbosComunes []string `json:"verbos_comunes"`
-			Auxiliares    []string `json:"verbos_auxiliares"`
-		} `json:"irregulares"`
-	} `json:"verbos"`
-	Sujeto            []string            `json:"sujeto"`
-	Complementos      Complementos        `json:"complementos"`
-	Preposiciones     []string            `json:"preposiciones"`
-	Articulos         []string            `json:"articulos"`
-	Adjetivos         map[string][]string `json:"adjetivos"`
-	Adverbios         map[string][]string `json:"adverbios"`
-	ExpresionesTiempo []string            `json:"expresiones_tiempo"`
-	ModalesPasados    []string            `json:"modales_pasados"` // Campo agregado para los verbos modales pasados
-}
-
-// Nuevo struct para modelar Complementos como un objeto en lugar de una lista
-type Complementos struct {
-	Objetos []string `json:"objetos"`
-	Lugares []string `json:"lugares"`
-	Comida  []string `json:"comida"`
-}
-
-// Inicializa el diccionario de palabras, asegurándose de hacerlo solo una vez
-func inicializarDiccionario() {
-	once.Do(func() {
-		diccionario = make(map[string]models.Palabra, 1000)
-
-		// Cargar las palabras desde el archivo JSON
-		wordsData, err := cargarPalabrasDesdeJSON("words.json")
-		if err != nil {
-			log.Fatal("Error loading words from JSON:", err)
-			return
+// agruparExpresiones une las palabras consecutivas que forman una expresión del
+// diccionario ("last night", "ice cream"), prefiriendo siempre la más larga
+func (d *Dictionary) agruparExpresiones(palabras []string) []string {
+	grupos := make([]string, 0, len(palabras))
+	for i := 0; i < len(palabras); {
+		n := min(d.longitudFrases[strings.ToLower(palabras[i])], len(palabras)-i)
+		for ; n > 1; n-- {
+			frase := strings.Join(palabras[i:i+n], " ")
+			if _, existe := d.palabras[strings.ToLower(frase)]; existe {
+				break
+			}
+		}
+		if n < 1 {
+			n = 1
 		}
 
-		// Agregar las palabras de cada categoría
-		agregarPalabras(wordsData.Sujeto, models.TipoSujeto)
-		agregarPalabras(wordsData.Verbos.Regulares, models.TipoVerboSimple)
-		agregarPalabras(wordsData.Verbos.Irregulares.VerbosComunes, models.TipoVerboSimple) // Verbos comunes
-		agregarPalabras(wordsData.Verbos.Irregulares.Auxiliares, models.TipoVerboAuxiliar)  // Verbos auxiliares
-		agregarPalabras(wordsData.Adjetivos["estado"], models.TipoVerboEstado)              // Si hay una categoría para Estado en la estructura
-		agregarPalabras(wordsData.ModalesPasados, models.TipoVerboModalPasado)              // Nuevos verbos modales
-		agregarPalabras(wordsData.ExpresionesTiempo, models.TipoTiempo)
-		agregarPalabras(wordsData.Preposiciones, models.TipoPreposicion)
-		agregarPalabras(wordsData.Articulos, models.TipoArticulo)
-		agregarPalabras(wordsData.Adjetivos["apariencia"], models.TipoAdjetivo)
-		agregarPalabras(wordsData.Adjetivos["personalidad"], models.TipoAdjetivo)
-		agregarPalabras(wordsData.Adjetivos["estado"], models.TipoAdjetivo)
-		agregarPalabras(wordsData.Adverbios["tiempo"], models.TipoAdverbio)
-		agregarPalabras(wordsData.Adverbios["modo"], models.TipoAdverbio)
-		agregarPalabras(wordsData.Adverbios["frecuencia"], models.TipoAdverbio)
+		grupos = append(grupos, strings.Join(palabras[i:i+n], " "))
+		i += n
+	}
 
-		// Agregar complementos
-		agregarPalabras(wordsData.Complementos.Objetos, models.TipoComplemento)
-		agregarPalabras(wordsData.Complementos.Lugares, models.TipoComplemento)
-		agregarPalabras(wordsData.Complementos.Comida, models.TipoComplemento)
+	return grupos
+}
 
-	})
+// Signos que se separan del principio y del final de cada palabra
+const (
+	signosApertura = `"'([{“‘¿¡«`
+	signosCierre   = `"')]}”’»,;:` + terminadores + "…"
+)
+
+// campo es una palabra del texto delimitada por espacios, con su posición en bytes
+type campo struct {
+	texto  string
+	inicio int
 }
 
-// Función para cargar palabras desde el archivo JSON
-func cargarPalabrasDesdeJSON(filepath string) (WordsData, error) {
-	var wordsData WordsData
-	file, err := os.ReadFile(filepath)
-	if err != nil {
-		return wordsData, err
+// separarCampos divide el texto por los espacios como strings.Fields, pero
+// conserva la posición de cada palabra
+func separarCampos(texto string) []campo {
+	var campos []campo
+	inicio := -1
+	for i, r := range texto {
+		switch {
+		case unicode.IsSpace(r) && inicio >= 0:
+			campos = append(campos, campo{texto[inicio:i], inicio})
+			inicio = -1
+		case !unicode.IsSpace(r) && inicio < 0:
+			inicio = i
+		}
+	}
+	if inicio >= 0 {
+		campos = append(campos, campo{texto[inicio:], inicio})
 	}
+	return campos
+}
+
+// separarPuntuacion divide cada palabra en los signos que la abren, la palabra y
+// los signos que la cierran ("yesterday," -> "yesterday" + ","). Los signos
+// finales seguidos (".", "?!", "...") forman un solo token, y no se separan el
+// punto de una abreviatura ("Mr.") ni los signos internos ("5.30", "didn't").
+func separarPuntuacion(campos []campo) []palabraLexica {
+	resultado := make([]palabraLexica, 0, len(campos))
+	for i, c := range campos {
+		palabra := c.texto
+		nucleo := strings.TrimLeft(palabra, signosApertura)
+		var fragmentos []string
+		for _, r := range palabra[:len(palabra)-len(nucleo)] {
+			fragmentos = append(fragmentos, string(r))
+		}
 
-	err = json.Unmarshal(file, &wordsData)
-	if err != nil {
-		return wordsData, err
+		sinCierre := strings.TrimRight(nucleo, signosCierre)
+		if i < len(campos)-1 && strings.HasPrefix(nucleo[len(sinCierre):], ".") && esAbreviatura(sinCierre) {
+			sinCierre += "."
+		}
+		if sinCierre != "" {
+			fragmentos = append(fragmentos, sinCierre)
+		}
+		fragmentos = append(fragmentos, agruparSignos(nucleo[len(sinCierre):])...)
+
+		// Solo el primer fragmento va separado por un espacio de la palabra anterior
+		inicio := c.inicio
+		for j, fragmento := range fragmentos {
+			resultado = append(resultado, palabraLexica{
+				texto:    fragmento,
+				original: fragmento,
+				pegada:   j > 0,
+				inicio:   inicio,
+				fin:      inicio + len(fragmento),
+			})
+			inicio += len(fragmento)
+		}
 	}
+	return resultado
+}
 
-	return wordsData, nil
+// agruparSignos divide los signos finales de una palabra en tokens: los
+// terminadores seguidos van juntos ("?!", "...") y el resto por separado
+func agruparSignos(signos string) []string {
+	var grupos []string
+	for _, r := range signos {
+		if n := len(grupos); n > 0 && esTerminador(r) && strings.ContainsAny(grupos[n-1], terminadores+"…") {
+			grupos[n-1] += string(r)
+			continue
+		}
+		grupos = append(grupos, string(r))
+	}
+	return grupos
 }
 
-// Función para agregar palabras al diccionario sin metadata
-func agregarPalabras(palabras []string, tipo models.TipoPalabra) {
-	agregarPalabrasConMetadata(palabras, tipo, models.Metadata{})
+// esAbreviatura indica si la palabra, sin su punto final, es una abreviatura
+// conocida o una inicial ("Mr", "e.g", "J")
+func esAbreviatura(palabra string) bool {
+	if utf8.RuneCountInString(palabra) == 1 {
+		inicial, _ := utf8.DecodeRuneInString(palabra)
+		return unicode.IsUpper(inicial) && palabra != "I"
+	}
+	return abreviaturas[strings.ToLower(palabra)]
 }
 
-// Función para agregar palabras con metadata
-func agregarPalabrasConMetadata(palabras []string, tipo models.TipoPalabra, metadata models.Metadata) {
-	mu.Lock()
-	defer mu.Unlock()
+// palabraLexica es una palabra de la oración preparada para el análisis léxico.
+// Una contracción se expande en varias; la primera conserva el texto original y
+// las demás quedan sin texto propio.
+type palabraLexica struct {
+	texto       string // Texto que se clasifica
+	original    string // Texto tal como aparece en la oración
+	contraccion bool
+	pegada      bool // Sin espacio respecto a la palabra anterior
+	inicio, fin int  // Bytes que ocupa en la oración
+}
 
+// expandirContracciones separa cada contracción conocida en las palabras que la
+// forman ("wasn't" -> "was" + "not"); el resto de palabras no cambia
+func (d *Dictionary) expandirContracciones(palabras []palabraLexica) []palabraLexica {
+	resultado := make([]palabraLexica, 0, len(palabras))
 	for _, palabra := range palabras {
-		diccionario[palabra] = models.Palabra{
-			Tipo:     tipo,
-			Texto:    palabra,
-			Metadata: metadata,
+		normalizada := strings.ToLower(strings.ReplaceAll(palabra.texto, "’", "'"))
+		expansion, existe := d.contracciones[normalizada]
+		if !existe {
+			resultado = append(resultado, palabra)
+			continue
 		}
+
+		for i, parte := range strings.Fields(expansion) {
+			p := palabraLexica{texto: parte, contraccion: true, pegada: true, inicio: palabra.inicio, fin: palabra.fin}
+			if i == 0 {
+				p.original, p.pegada = palabra.original, palabra.pegada
+			}
+			resultado = append(resultado, p)
+		}
+	}
+	return resultado
+}
+
+// unirOriginales devuelve el texto original de un grupo de palabras léxicas e
+// indica si alguna proviene de una contracción
+func unirOriginales(grupo []palabraLexica) (string, bool) {
+	var original strings.Builder
+	contraccion := false
+	for i, p := range grupo {
+		if i > 0 && !p.pegada {
+			original.WriteByte(' ')
+		}
+		original.WriteString(p.original)
+		contraccion = contraccion || p.contraccion
 	}
+	return original.String(), contraccion
+}
+
+// esPuntuacion indica si el texto está formado solo por signos de puntuación
+func esPuntuacion(texto string) bool {
+	return texto != "" && strings.IndexFunc(texto, func(r rune) bool { return !unicode.IsPunct(r) }) == -1
 }
 
 // Función de preprocesamiento del texto
 func preprocesarTexto(texto string) string {
 	palabras := strings.Fields(texto)
 	for i, palabra := range palabras {
-		if !esPosibleNombrePropio(palabra) {
-			palabras[i] = strings.ToLower(palabra)
-		}
+		palabras[i] = normalizarPalabra(palabra)
 	}
 	return strings.Join(palabras, " ")
 }
 
+// normalizarPalabra pasa la palabra a minúsculas salvo que pueda ser un nombre propio
+func normalizarPalabra(palabra string) string {
+	if esPosibleNombrePropio(palabra) {
+		return palabra
+	}
+	return strings.ToLower(palabra)
+}
+
 // Verificar si una palabra puede ser un nombre propio
 func esPosibleNombrePropio(palabra string) bool {
 	return len(palabra) > 0 && unicode.IsUpper(rune(palabra[0]))
@@ -150,16 +235,40 @@ func obtenerContextoPalabra(palabras []string, tokens []models.Token, posicion i
 	return contexto
 }
 
-// Clasificar palabra basándonos en su contexto
+// BuscarPalabra consulta una palabra o expresión en el diccionario del paquete
+func BuscarPalabra(palabra string) (models.Palabra, bool) {
+	return diccionarioActual().BuscarPalabra(palabra)
+}
+
+// BuscarPalabra consulta una palabra o expresión en el diccionario, sin las
+// reglas de contexto ni de sufijos de ClasificarPalabra
+func (d *Dictionary) BuscarPalabra(palabra string) (models.Palabra, bool) {
+	clave := normalizarClave(palabra)
+	clasificacion, existe := d.buscar(clave)
+
+	if !existe {
+		return models.Palabra{Tipo: models.TipoDesconocido, Texto: clave, Original: palabra}, false
+	}
+	clasificacion.Original = palabra
+	return clasificacion, true
+}
+
+// ClasificarPalabra clasifica una palabra según su contexto con el validador por defecto
 func ClasificarPalabra(palabra string, ctx models.Contexto) models.Palabra {
-	inicializarDiccionario()
+	return porDefecto.ClasificarPalabra(palabra, ctx)
+}
+
+// ClasificarPalabra clasifica una palabra según su contexto
+func (v *Validator) ClasificarPalabra(palabra string, ctx models.Contexto) models.Palabra {
+	return v.Diccionario().clasificar(palabra, ctx)
+}
 
+// Clasificar palabra basándonos en su contexto
+func (d *Dictionary) clasificar(palabra string, ctx models.Contexto) models.Palabra {
 	palabraOriginal := palabra
 	palabra = strings.ToLower(strings.TrimSpace(palabra))
 
-	mu.RLock()
-	clasificacion, existe := diccionario[palabra]
-	mu.RUnlock()
+	clasificacion, existe := d.buscar(palabra)
 
 	if existe {
 		clasificacion.Original = palabraOriginal
@@ -167,8 +276,10 @@ func ClasificarPalabra(palabra string, ctx models.Contexto) models.Palabra {
 		return clasificacion
 	}
 
-	// Clasificación de palabras con sufijos
+	// Clasificación de signos de puntuación y palabras con sufijos
 	switch {
+	case esPuntuacion(palabra):
+		return models.Palabra{Tipo: models.TipoPuntuacion, Texto: palabra, Original: palabraOriginal, Posicion: ctx.PosicionEnOracion}
 	case strings.HasSuffix(palabra, "ly"):
 		return models.Palabra{Tipo: models.TipoAdverbio, Texto: palabra, Original: palabraOriginal, Posicion: ctx.PosicionEnOracion}
 	case strings.HasSuffix(palabra, "ed"):
@@ -188,23 +299,41 @@ func ClasificarPalabra(palabra string, ctx models.Contexto) models.Palabra {
 	return models.Palabra{Tipo: models.TipoDesconocido, Texto: palabra, Original: palabraOriginal, Posicion: ctx.PosicionEnOracion}
 }
 
-// Análisis léxico de una oración
+// AnalizarLexico hace el análisis léxico de una oración con el validador por defecto
 func AnalizarLexico(oracion string) ([]models.Token, error) {
+	return porDefecto.AnalizarLexico(oracion)
+}
+
+// AnalizarLexico divide la oración en tokens clasificados con el diccionario
+// del validador
+func (v *Validator) AnalizarLexico(oracion string) ([]models.Token, error) {
+	return v.Diccionario().analizarLexico(oracion)
+}
+
+// Análisis léxico de una oración
+func (d *Dictionary) analizarLexico(oracion string) ([]models.Token, error) {
 	if strings.TrimSpace(oracion) == "" {
 		return nil, &models.ErrorAnalisis{
-			Mensaje:  "la oración está vacía",
+			Mensaje:  "Error in lexical analysis: the sentence is empty",
 			Posicion: 0,
 			Contexto: "",
 		}
 	}
 
-	oracion = preprocesarTexto(oracion)
-	palabras := strings.Fields(oracion)
+	lexicas := d.expandirContracciones(separarPuntuacion(separarCampos(oracion)))
+	textos := make([]string, len(lexicas))
+	for i, p := range lexicas {
+		lexicas[i].original = normalizarPalabra(p.original)
+		textos[i] = normalizarPalabra(p.texto)
+	}
+
+	palabras := d.agruparExpresiones(textos)
 	tokens := make([]models.Token, 0, len(palabras))
 
+	siguiente := 0 // Primera palabra léxica del grupo actual
 	for i, palabra := range palabras {
 		ctx := obtenerContextoPalabra(palabras, tokens, i)
-		p := ClasificarPalabra(palabra, ctx)
+		p := d.clasificar(palabra, ctx)
 
 		token := models.Token{
 			Tipo:     p.Tipo,
@@ -214,15 +343,153 @@ func AnalizarLexico(oracion string) ([]models.Token, error) {
 			Metadata: p.Metadata,
 		}
 
+		n := len(strings.Fields(palabra))
+		grupo := lexicas[siguiente : siguiente+n]
+		token.Original, token.Metadata.EsContraccion = unirOriginales(grupo)
+		token.Metadata.PegadoAlAnterior = grupo[0].pegada
+		token.ByteInicio, token.ByteFin = grupo[0].inicio, grupo[n-1].fin
+		token.RunaInicio = utf8.RuneCountInString(oracion[:token.ByteInicio])
+		token.RunaFin = token.RunaInicio + utf8.RuneCountInString(oracion[token.ByteInicio:token.ByteFin])
+		siguiente += n
+
 		tokens = append(tokens, token)
 	}
 
 	return tokens, nil
 }
 
-func ValidarTokens(tokens []models.Token) (string, string) {
+// List of disallowed auxiliaries, shared by every sentence type
+var auxiliaresNoPermitidos = map[string]bool{
+	"has":  true,
+	"have": true,
+	"had":  true,
+	"do":   true,
+	"does": true,
+	"did":  true,
+	"am":   true,
+	"is":   true,
+	"are":  true,
+}
+
+// Negative words not allowed in affirmative sentences
+var palabrasNegativas = map[string]bool{
+	"not":   true,
+	"never": true,
+	"no":    true,
+}
+
+// Word types allowed between the subject and was/were
+var posicionesPermitidas = map[models.TipoPalabra]bool{
+	models.TipoPreposicion: true,
+	models.TipoComplemento: true,
+	models.TipoArticulo:    true,
+	models.TipoAdjetivo:    true,
+}
+
+// Conjugation rules for past tense verbs
+var reglasConjugacion = map[string]map[string]bool{
+	"I": {
+		"was": true,
+	},
+	"he": {
+		"was": true,
+	},
+	"she": {
+		"was": true,
+	},
+	"it": {
+		"was": true,
+	},
+	"you": {
+		"were": true,
+	},
+	"we": {
+		"were": true,
+	},
+	"they": {
+		"were": true,
+	},
+}
+
+// Canonical mapping of pronouns
+var mapaPronombres = map[string]string{
+	"i":    "I",
+	"he":   "he",
+	"she":  "she",
+	"it":   "it",
+	"you":  "you",
+	"we":   "we",
+	"they": "they",
+}
+
+// nombreSujeto es como los mensajes de todos los perfiles nombran al sujeto:
+// los pronombres personales normalizados ("they", "I") y los demás sujetos
+// como se escribieron
+func nombreSujeto(sujeto string) string {
+	if pronombre, existe := mapaPronombres[strings.ToLower(sujeto)]; existe {
+		return pronombre
+	}
+	return sujeto
+}
+
+// verboWasWere devuelve el pronombre normalizado y la forma de was/were que
+// concuerda con él; existe es false si el sujeto no es un pronombre personal
+func verboWasWere(sujeto string) (pronombre, verbo string, existe bool) {
+	pronombre, existe = mapaPronombres[strings.ToLower(sujeto)]
+	if !existe {
+		return "", "", false
+	}
+	for verbo := range reglasConjugacion[pronombre] {
+		return pronombre, verbo, true
+	}
+	return pronombre, "", false
+}
+
+// ValidarTokens valida la estructura de la oración con el validador por defecto
+func ValidarTokens(tokens []models.Token, opciones ...Opcion) (string, string) {
+	return porDefecto.ValidarTokens(tokens, opciones...)
+}
+
+// ValidarTokens valida la estructura de la oración y devuelve "Valid" o "Invalid" junto a una explicación
+func (v *Validator) ValidarTokens(tokens []models.Token, opciones ...Opcion) (string, string) {
+	if diagnostico, existe := primerError(v.DiagnosticarTokens(tokens, opciones...)); existe {
+		return "Invalid", diagnostico.Mensaje
+	}
+	return "Valid", ExplicacionValida(v.con(opciones)...)
+}
+
+// DiagnosticarTokens valida la estructura de la oración con el validador por defecto
+func DiagnosticarTokens(tokens []models.Token, opciones ...Opcion) []models.Diagnostic {
+	return porDefecto.DiagnosticarTokens(tokens, opciones...)
+}
+
+// DiagnosticarTokens valida la estructura de la oración y devuelve los problemas
+// encontrados como diagnósticos con un identificador de regla estable. Por defecto
+// se detiene en el primer error; ConTodosLosErrores ejecuta todas las reglas.
+// ConPerfil elige el ejercicio cuyas reglas se aplican y ConPuntuacionEstricta
+// añade las reglas de puntuación.
+func (v *Validator) DiagnosticarTokens(tokens []models.Token, opciones ...Opcion) []models.Diagnostic {
+	return diagnosticar(v.Diccionario(), tokens, v.con(opciones))
+}
+
+// diagnosticar aplica las reglas del perfil y la puntuación con el diccionario
+// indicado
+func diagnosticar(d *Dictionary, tokens []models.Token, opciones []Opcion) []models.Diagnostic {
+	v := nuevaValidacion(d, opciones)
+
+	diagnosticos := v.opciones.perfil.reglas(tokens, v)
+	if v.opciones.puntuacionEstricta && len(tokens) > 0 && !v.detenida() {
+		diagnosticos = diagnosticarPuntuacion(tokens, v)
+	}
+	completarCorrecciones(diagnosticos, textoTokens(tokens))
+	return diagnosticos
+}
+
+// diagnosticarAfirmativo aplica las reglas del pasado simple afirmativo
+func diagnosticarAfirmativo(tokens []models.Token, v *validacion) []models.Diagnostic {
 	if len(tokens) == 0 {
-		return "Invalid", "No tokens found."
+		v.reportar(nuevoDiagnostico(ReglaSinTokens, "No tokens found.", tokens, 0, 0))
+		return v.diagnosticos
 	}
 
 	// Initialize sentence elements
@@ -235,84 +502,45 @@ func ValidarTokens(tokens []models.Token) (string, string) {
 		models.TipoNegativo:         {Encontrado: false, Posicion: -1},
 	}
 
-	// List of disallowed auxiliaries
-	auxiliaresNoPermitidos := map[string]bool{
-		"has":  true,
-		"have": true,
-		"had":  true,
-		"do":   true,
-		"does": true,
-		"did":  true,
-		"am":   true,
-		"is":   true,
-		"are":  true,
-	}
-
-	// Negative words
-	palabrasNegativas := map[string]bool{
-		"not":   true,
-		"never": true,
-		"no":    true,
-	}
-
-	// Conjugation rules for past tense verbs
-	reglasConjugacion := map[string]map[string]bool{
-		"I": {
-			"was": true,
-		},
-		"he": {
-			"was": true,
-		},
-		"she": {
-			"was": true,
-		},
-		"it": {
-			"was": true,
-		},
-		"you": {
-			"were": true,
-		},
-		"we": {
-			"were": true,
-		},
-		"they": {
-			"were": true,
-		},
-	}
-
-	// Canonical mapping of pronouns
-	mapaPronombres := map[string]string{
-		"i":    "I",
-		"he":   "he",
-		"she":  "she",
-		"it":   "it",
-		"you":  "you",
-		"we":   "we",
-		"they": "they",
-	}
-
 	// Variables to track important details
 	primeraAparicionWasWere := -1
 	primerSujeto := -1
 	sujetoTexto := ""
 	verboPasadoTexto := ""
-	posicionesPermitidas := map[models.TipoPalabra]bool{
-		models.TipoPreposicion: true,
-		models.TipoComplemento: true,
-		models.TipoArticulo:    true,
-		models.TipoAdjetivo:    true,
-	}
+
+	// A base verb after an auxiliary or a negative is already reported through them
+	expresionPasada := buscarExpresionPasada(tokens)
+	auxiliarPrevio := false
 
 	// Traverse tokens and update elements
 	for i, token := range tokens {
-		// Check for negative words
-		if palabrasNegativas[token.Texto] {
-			return "Invalid", "Negative constructions are not allowed in affirmative sentences."
+		// Check for negative words and constructions ("did not", "didn't")
+		if palabrasNegativas[token.Texto] || token.Tipo == models.TipoNegativo {
+			auxiliarPrevio = true
+			if v.reportar(nuevoDiagnostico(ReglaNegacion,
+				"Negative constructions are not allowed in affirmative sentences.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
 		}
 
-		// Check for disallowed auxiliaries
+		// Check for disallowed auxiliaries and present-tense verbs
 		if auxiliaresNoPermitidos[token.Texto] {
-			return "Invalid", "Auxiliary verbs are not allowed in affirmative simple past sentences."
+			auxiliarPrevio = true
+			if v.reportar(nuevoDiagnostico(ReglaAuxiliar,
+				"The sentence should not contain auxiliary verbs.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+		} else if esPresente(token) && !auxiliarPrevio {
+			if v.reportar(diagnosticoTiempoVerbal(v.diccionario, tokens, i, expresionPasada)) {
+				return v.diagnosticos
+			}
+		}
+
+		// Check for over-regularized irregular verbs ("goed", "eated")
+		if pasado, existe := v.diccionario.formaSobrerregularizada(token.Texto); existe {
+			if v.reportar(diagnosticoPasadoIrregular(tokens, i, pasado)) {
+				return v.diagnosticos
+			}
 		}
 
 		// Update first appearance of was/were
@@ -354,48 +582,63 @@ func ValidarTokens(tokens []models.Token) (string, string) {
 
 	// Strict validations
 	// Ensure correct usage of was/were based on personal pronouns
+	sujetoReportado := false
 	if primeraAparicionWasWere != -1 {
 		// Validate was/were usage
 		if sujetoTexto == "" {
-			return "Invalid", "No subject found for verb validation."
-		}
-
-		// Normalizar el sujeto usando el mapa de pronombres
-		pronombre, existe := mapaPronombres[sujetoTexto]
-		if !existe {
-			return "Invalid", "Unrecognized subject pronoun."
-		}
-
-		// Verificar las reglas de conjugación
-		if reglasConjugacion[pronombre] == nil || !reglasConjugacion[pronombre][verboPasadoTexto] {
-			// Determinar el verbo correcto
+			sujetoReportado = true
+			if v.reportar(nuevoDiagnostico(ReglaSujetoFaltante,
+				"No subject found for verb validation.", tokens, primeraAparicionWasWere, primeraAparicionWasWere+1)) {
+				return v.diagnosticos
+			}
+		} else if pronombre, existe := mapaPronombres[sujetoTexto]; !existe {
+			// Normalizar el sujeto usando el mapa de pronombres
+			if v.reportar(nuevoDiagnostico(ReglaPronombreDesconocido,
+				"Unrecognized subject pronoun.", tokens, primerSujeto, primerSujeto+1)) {
+				return v.diagnosticos
+			}
+		} else if reglasConjugacion[pronombre] == nil || !reglasConjugacion[pronombre][verboPasadoTexto] {
+			// Verificar las reglas de conjugación y determinar el verbo correcto
 			verbosCorrectos := []string{}
 			for verbo := range reglasConjugacion[pronombre] {
 				verbosCorrectos = append(verbosCorrectos, verbo)
 			}
 
-			return "Invalid", fmt.Sprintf("Incorrect verb form for '%s'. Use '%s'.",
-				pronombre,
-				verbosCorrectos[0],
-			)
+			if v.reportar(diagnosticoFormaSer(tokens, primeraAparicionWasWere, ReglaConcordanciaWasWere,
+				fmt.Sprintf("Incorrect verb form for '%s'. Use '%s'.", nombreSujeto(pronombre), verbosCorrectos[0]),
+				verbosCorrectos[0])) {
+				return v.diagnosticos
+			}
 		}
 
-		// Ensure there is a subject before was/were
-		if primerSujeto == -1 || primerSujeto >= primeraAparicionWasWere {
-			return "Invalid", "A subject is missing before the verb 'was' or 'were'."
-		}
+		if sujetoTexto != "" {
+			// Ensure there is a subject before was/were
+			if primerSujeto >= primeraAparicionWasWere {
+				if v.reportar(nuevoDiagnostico(ReglaOrdenSujetoVerbo,
+					"A subject is missing before the verb 'was' or 'were'.", tokens, primeraAparicionWasWere, primeraAparicionWasWere+1)) {
+					return v.diagnosticos
+				}
+			}
 
-		// Ensure no disallowed tokens between subject and verb
-		for i := primerSujeto + 1; i < primeraAparicionWasWere; i++ {
-			if !posicionesPermitidas[tokens[i].Tipo] {
-				return "Invalid", "The verb must immediately follow the subject."
+			// Ensure no disallowed tokens between subject and verb
+			for i := primerSujeto + 1; i < primeraAparicionWasWere; i++ {
+				if !posicionesPermitidas[tokens[i].Tipo] {
+					if v.reportar(nuevoDiagnostico(ReglaVerboTrasSujeto,
+						"The verb must immediately follow the subject.", tokens, i, i+1)) {
+						return v.diagnosticos
+					}
+					break
+				}
 			}
 		}
 	}
 
 	// Ensure the sentence has a subject
-	if !elementos[models.TipoSujeto].Encontrado {
-		return "Invalid", "The subject is missing in the sentence."
+	if !elementos[models.TipoSujeto].Encontrado && !sujetoReportado {
+		if v.reportar(nuevoDiagnostico(ReglaSujetoFaltante,
+			"The subject is missing in the sentence.", tokens, 0, len(tokens))) {
+			return v.diagnosticos
+		}
 	}
 
 	// Ensure there is at least one verb (including was/were)
@@ -404,22 +647,82 @@ func ValidarTokens(tokens []models.Token) (string, string) {
 	tieneVerboModalPasado := elementos[models.TipoVerboModalPasado].Encontrado
 
 	if !tieneVerboSimple && !tieneVerboEstado && !tieneVerboModalPasado {
-		return "Invalid", "A past tense verb is missing in the sentence."
+		if v.reportar(nuevoDiagnostico(ReglaVerboFaltante,
+			"A past tense verb is missing in the sentence.", tokens, 0, len(tokens))) {
+			return v.diagnosticos
+		}
+	}
+
+	primerVerbo := -1
+	for i, token := range tokens {
+		if esVerbo(token) {
+			primerVerbo = i
+			break
+		}
+	}
+
+	// Ensure the subject opens the clause and comes before the verb
+	if primerSujeto != -1 && primerVerbo != -1 {
+		if primerVerbo < primerSujeto {
+			// El caso de was/were ya se reportó junto a su concordancia
+			if primerVerbo != primeraAparicionWasWere && v.reportar(nuevoDiagnostico(ReglaOrdenSujetoVerbo,
+				"The verb must follow the subject.", tokens, primerVerbo, primerVerbo+1)) {
+				return v.diagnosticos
+			}
+		} else {
+			for i := 0; i < primerSujeto; i++ {
+				if tokens[i].Tipo == models.TipoDesconocido {
+					if v.reportar(nuevoDiagnostico(ReglaOrdenSujetoVerbo,
+						"The verb must follow the subject.", tokens, i, i+1)) {
+						return v.diagnosticos
+					}
+					break
+				}
+			}
+		}
+	}
+
+	// Ensure complements come after the verb
+	for i := 0; i < primerVerbo; i++ {
+		if tokens[i].Tipo == models.TipoComplemento {
+			if v.reportar(nuevoDiagnostico(ReglaComplementoAntesDelVerbo,
+				"The complement must come after the verb.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+			break
+		}
 	}
 
-	// Ensure no incorrect negative constructions
-	if elementos[models.TipoNegativo].Encontrado && elementos[models.TipoVerboSimple].Encontrado {
-		return "Invalid", "The sentence cannot contain both modal verbs and negatives in the same structure."
+	return v.diagnosticos
+}
+
+// esVerbo indica si el token corresponde a alguna forma verbal (incluyendo was/were)
+func esVerbo(token models.Token) bool {
+	switch token.Tipo {
+	case models.TipoVerboSimple, models.TipoVerboEstado, models.TipoVerboModalPasado:
+		return true
 	}
+	return token.Texto == "was" || token.Texto == "were"
+}
 
-	return "Valid", "The sentence has a valid structure in affirmative simple past."
+// ValidarOracion valida la oración completa con el validador por defecto
+func ValidarOracion(oracion string, opciones ...Opcion) (string, string) {
+	return porDefecto.ValidarOracion(oracion, opciones...)
 }
 
-// Function to validate the entire sentence
-func ValidarOracion(oracion string) (string, string) {
-	tokens, err := AnalizarLexico(oracion)
-	if err != nil {
-		return "Invalid", "Error in lexical analysis: " + err.Error()
+// Function to validate the entire sentence. With ConTodosLosErrores the
+// explanation lists every problem found, one after another.
+func (v *Validator) ValidarOracion(oracion string, opciones ...Opcion) (string, string) {
+	diagnosticos := v.DiagnosticarOracion(oracion, opciones...)
+	if EsValida(diagnosticos) {
+		return "Valid", ExplicacionValida(v.con(opciones)...)
+	}
+
+	mensajes := make([]string, 0, len(diagnosticos))
+	for _, d := range diagnosticos {
+		if d.Severidad == models.SeveridadError {
+			mensajes = append(mensajes, d.Mensaje)
+		}
 	}
-	return ValidarTokens(tokens)
+	return "Invalid", strings.Join(mensajes, " ")
 }
@@ -1,6 +1,8 @@
 package validators
 
 import (
+	"maps"
+	"os"
 	"reflect"
 	"testing"
 	"validar_oraciones/models"
@@ -19,6 +21,22 @@ const (
 	ErrLexicalAnalysisEmpty      = "Error in lexical analysis: the sentence is empty"
 )
 
+// TestMain points the dictionary at the repository's words.json
+func TestMain(m *testing.M) {
+	rutaDiccionario = "../words.json"
+	os.Exit(m.Run())
+}
+
+// perfil busca un perfil registrado o detiene la prueba
+func perfil(t *testing.T, nombre string) Perfil {
+	t.Helper()
+	p, err := BuscarPerfil(nombre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
 // TestPreprocesarTexto tests preprocessing of text for various cases
 func TestPreprocesarTexto(t *testing.T) {
 	tests := []struct {
@@ -77,9 +95,11 @@ func TestClasificarPalabra(t *testing.T) {
 		expected models.Palabra
 	}{
 		{"known simple verb", "played", models.Contexto{PosicionEnOracion: 1},
-			models.Palabra{Tipo: models.TipoVerboSimple, Texto: "played", Original: "played", Posicion: 1}},
-		{"proper noun", "John", models.Contexto{PosicionEnOracion: 0},
-			models.Palabra{Tipo: models.TipoSujeto, Texto: "john", Original: "John", Posicion: 0, Metadata: models.Metadata{EsNombrePropio: true}}},
+			models.Palabra{Tipo: models.TipoVerboSimple, Texto: "played", Original: "played", Posicion: 1, Metadata: models.Metadata{Lema: "play", Forma: models.FormaPasado}}},
+		{"capitalized dictionary subject", "John", models.Contexto{PosicionEnOracion: 0},
+			models.Palabra{Tipo: models.TipoSujeto, Texto: "john", Original: "John", Posicion: 0}},
+		{"proper noun", "Peter", models.Contexto{PosicionEnOracion: 0},
+			models.Palabra{Tipo: models.TipoSujeto, Texto: "peter", Original: "Peter", Posicion: 0, Metadata: models.Metadata{EsNombrePropio: true}}},
 		{"word after article", "house", models.Contexto{PosicionEnOracion: 1, TipoAnterior: models.TipoArticulo},
 			models.Palabra{Tipo: models.TipoComplemento, Texto: "house", Original: "house", Posicion: 1}},
 	}
@@ -106,6 +126,7 @@ func TestAnalizarLexico(t *testing.T) {
 		{"valid sentence", "John played football", 3, false, ""},
 		{"empty sentence", "", 0, true, ErrLexicalAnalysisEmpty},
 		{"sentence with multiple spaces", "I    played   football    yesterday", 4, false, ""},
+		{"terminator is a separate token", "Did you play football?", 5, false, ""},
 	}
 
 	for _, tt := range tests {
@@ -133,6 +154,64 @@ func TestAnalizarLexico(t *testing.T) {
 	}
 }
 
+// TestAnalizarLexicoExpresiones tests longest-match grouping of multi-word dictionary entries
+func TestAnalizarLexicoExpresiones(t *testing.T) {
+	tests := []struct {
+		name     string
+		oracion  string
+		expected []models.Token
+	}{
+		{
+			"compound noun and time expression",
+			"I ate ice cream last night",
+			[]models.Token{
+				{Tipo: models.TipoSujeto, Texto: "i", Original: "I", Posicion: 0, ByteInicio: 0, ByteFin: 1, RunaInicio: 0, RunaFin: 1},
+				{Tipo: models.TipoVerboSimple, Texto: "ate", Original: "ate", Posicion: 1, ByteInicio: 2, ByteFin: 5, RunaInicio: 2, RunaFin: 5, Metadata: models.Metadata{SubTipo: SubTipoIrregular, Lema: "eat", Forma: models.FormaPasado}},
+				{Tipo: models.TipoComplemento, Texto: "ice cream", Original: "ice cream", Posicion: 2, ByteInicio: 6, ByteFin: 15, RunaInicio: 6, RunaFin: 15},
+				{Tipo: models.TipoTiempo, Texto: "last night", Original: "last night", Posicion: 3, ByteInicio: 16, ByteFin: 26, RunaInicio: 16, RunaFin: 26},
+			},
+		},
+		{
+			"longest expression wins",
+			"They arrived two days ago",
+			[]models.Token{
+				{Tipo: models.TipoSujeto, Texto: "they", Original: "They", Posicion: 0, ByteInicio: 0, ByteFin: 4, RunaInicio: 0, RunaFin: 4},
+				{Tipo: models.TipoVerboSimple, Texto: "arrived", Original: "arrived", Posicion: 1, ByteInicio: 5, ByteFin: 12, RunaInicio: 5, RunaFin: 12, Metadata: models.Metadata{Lema: "arrive", Forma: models.FormaPasado}},
+				{Tipo: models.TipoTiempo, Texto: "two days ago", Original: "two days ago", Posicion: 2, ByteInicio: 13, ByteFin: 25, RunaInicio: 13, RunaFin: 25},
+			},
+		},
+		{
+			"capitalized expression",
+			"The teacher helped",
+			[]models.Token{
+				{Tipo: models.TipoSujeto, Texto: "the teacher", Original: "The teacher", Posicion: 0, ByteInicio: 0, ByteFin: 11, RunaInicio: 0, RunaFin: 11},
+				{Tipo: models.TipoVerboSimple, Texto: "helped", Original: "helped", Posicion: 1, ByteInicio: 12, ByteFin: 18, RunaInicio: 12, RunaFin: 18, Metadata: models.Metadata{Lema: "help", Forma: models.FormaPasado}},
+			},
+		},
+		{
+			"partial expression falls back to single words",
+			"I saw ice",
+			[]models.Token{
+				{Tipo: models.TipoSujeto, Texto: "i", Original: "I", Posicion: 0, ByteInicio: 0, ByteFin: 1, RunaInicio: 0, RunaFin: 1},
+				{Tipo: models.TipoVerboSimple, Texto: "saw", Original: "saw", Posicion: 1, ByteInicio: 2, ByteFin: 5, RunaInicio: 2, RunaFin: 5, Metadata: models.Metadata{SubTipo: SubTipoIrregular, Lema: "see", Forma: models.FormaPasado}},
+				{Tipo: models.TipoDesconocido, Texto: "ice", Original: "ice", Posicion: 2, ByteInicio: 6, ByteFin: 9, RunaInicio: 6, RunaFin: 9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := AnalizarLexico(tt.oracion)
+			if err != nil {
+				t.Fatalf("AnalizarLexico() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(tokens, tt.expected) {
+				t.Errorf("AnalizarLexico() = %+v, expected %+v", tokens, tt.expected)
+			}
+		})
+	}
+}
+
 // TestValidarTokens tests sentence validation logic for various cases
 func TestValidarTokens(t *testing.T) {
 	tests := []struct {
@@ -228,6 +307,12 @@ func TestValidarOracion(t *testing.T) {
 			"Invalid",
 			ErrIncorrectOrderSubjectVerb,
 		},
+		{
+			"valid sentence with multi-word expressions",
+			"I ate ice cream last night",
+			"Valid",
+			"The sentence has a valid structure in the simple past affirmative.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,3 +325,724 @@ func TestValidarOracion(t *testing.T) {
 		})
 	}
 }
+
+// TestValidarOracionTodosLosErrores tests that the accumulating mode joins every error message
+func TestValidarOracionTodosLosErrores(t *testing.T) {
+	tests := []struct {
+		name           string
+		oracion        string
+		opciones       []Opcion
+		expectedStatus string
+		expectedMsg    string
+	}{
+		{
+			"first error only",
+			"they was not happy",
+			nil,
+			"Invalid",
+			"Negative constructions are not allowed in affirmative sentences.",
+		},
+		{
+			"all errors",
+			"they was not happy",
+			[]Opcion{ConTodosLosErrores()},
+			"Invalid",
+			"Negative constructions are not allowed in affirmative sentences. Incorrect verb form for 'they'. Use 'were'.",
+		},
+		{
+			"valid sentence",
+			"I played football",
+			[]Opcion{ConTodosLosErrores()},
+			"Valid",
+			"The sentence has a valid structure in the simple past affirmative.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, msg := ValidarOracion(tt.oracion, tt.opciones...)
+			if status != tt.expectedStatus || msg != tt.expectedMsg {
+				t.Errorf("ValidarOracion() = %v, %v, expected %v, %v", status, msg, tt.expectedStatus, tt.expectedMsg)
+			}
+		})
+	}
+}
+
+// TestDiagnosticarTokens tests that validation failures carry stable rule IDs and spans
+func TestDiagnosticarTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokens   []models.Token
+		expected []models.Diagnostic
+	}{
+		{
+			"valid sentence",
+			[]models.Token{{Tipo: models.TipoSujeto, Texto: "I"}, {Tipo: models.TipoVerboSimple, Texto: "played"}},
+			nil,
+		},
+		{
+			"no tokens",
+			[]models.Token{},
+			[]models.Diagnostic{{Regla: ReglaSinTokens, Severidad: models.SeveridadError, Mensaje: ErrNoTokensFound}},
+		},
+		{
+			"was/were agreement with suggestion",
+			[]models.Token{{Tipo: models.TipoSujeto, Texto: "they"}, {Tipo: models.TipoDesconocido, Texto: "was"}, {Tipo: models.TipoAdjetivo, Texto: "happy"}},
+			[]models.Diagnostic{{
+				Regla:      ReglaConcordanciaWasWere,
+				Severidad:  models.SeveridadError,
+				Mensaje:    "Incorrect verb form for 'they'. Use 'were'.",
+				Rango:      models.Rango{PalabraInicio: 1, PalabraFin: 2, ByteInicio: 5, ByteFin: 8, RunaInicio: 5, RunaFin: 8},
+				Sugerencia: "were",
+				Correccion: "they were happy",
+			}},
+		},
+		{
+			"span after multi-word token",
+			[]models.Token{{Tipo: models.TipoSujeto, Texto: "the teacher"}, {Tipo: models.TipoVerboAuxiliar, Texto: "did"}},
+			[]models.Diagnostic{{
+				Regla:     ReglaAuxiliar,
+				Severidad: models.SeveridadError,
+				Mensaje:   ErrNoAuxiliaryVerbs,
+				Rango:     models.Rango{PalabraInicio: 2, PalabraFin: 3, ByteInicio: 12, ByteFin: 15, RunaInicio: 12, RunaFin: 15},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarTokens(tt.tokens)
+			if !reflect.DeepEqual(diagnosticos, tt.expected) {
+				t.Errorf("DiagnosticarTokens() = %+v, expected %+v", diagnosticos, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDiagnosticarOracion tests that byte spans point into the original sentence
+func TestDiagnosticarOracion(t *testing.T) {
+	oracion := "They   was at the park"
+	diagnosticos := DiagnosticarOracion(oracion)
+
+	if len(diagnosticos) != 1 {
+		t.Fatalf("DiagnosticarOracion() returned %d diagnostics, expected 1", len(diagnosticos))
+	}
+	rango := diagnosticos[0].Rango
+	if got := oracion[rango.ByteInicio:rango.ByteFin]; got != "was" {
+		t.Errorf("DiagnosticarOracion() span = %q, expected %q", got, "was")
+	}
+}
+
+// TestDiagnosticarTokensTodosLosErrores tests that the accumulating mode reports every problem
+func TestDiagnosticarTokensTodosLosErrores(t *testing.T) {
+	tests := []struct {
+		name     string
+		oracion  string
+		expected []string
+	}{
+		{"valid sentence", "I played football", nil},
+		{"negative construction", "I did not play", []string{ReglaNegacion}},
+		{"negative and agreement", "they was not happy", []string{ReglaNegacion, ReglaConcordanciaWasWere}},
+		{"present verbs", "She walks and goes home", []string{ReglaTiempoVerbal, ReglaTiempoVerbal}},
+		{"agreement only", "they was at the park", []string{ReglaConcordanciaWasWere}},
+		{"missing subject and verb", "the ball", []string{ReglaSujetoFaltante, ReglaVerboFaltante}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := AnalizarLexico(tt.oracion)
+			if err != nil {
+				t.Fatalf("AnalizarLexico() unexpected error = %v", err)
+			}
+
+			var reglas []string
+			for _, d := range DiagnosticarTokens(tokens, ConTodosLosErrores()) {
+				reglas = append(reglas, d.Regla)
+			}
+			if !reflect.DeepEqual(reglas, tt.expected) {
+				t.Errorf("DiagnosticarTokens() rules = %v, expected %v", reglas, tt.expected)
+			}
+		})
+	}
+}
+
+// TestSugerencias tests corrections for over-regularized verbs and was/were agreement
+func TestSugerencias(t *testing.T) {
+	tests := []struct {
+		name       string
+		oracion    string
+		regla      string
+		sugerencia string
+		correccion string
+	}{
+		{"over-regularized go", "I goed to school", ReglaPasadoIrregular, "went", "I went to school"},
+		{"over-regularized eat", "She eated the cake", ReglaPasadoIrregular, "ate", "She ate the cake"},
+		{"doubled consonant", "We swimmed yesterday", ReglaPasadoIrregular, "swam", "We swam yesterday"},
+		{"e-drop", "They maked a cake", ReglaPasadoIrregular, "made", "They made a cake"},
+		{"was/were agreement", "They  was at the park", ReglaConcordanciaWasWere, "were", "They  were at the park"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion)
+			if len(diagnosticos) == 0 {
+				t.Fatalf("DiagnosticarOracion(%q) returned no diagnostics", tt.oracion)
+			}
+
+			d := diagnosticos[0]
+			if d.Regla != tt.regla || d.Sugerencia != tt.sugerencia || d.Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion() = %s/%q/%q, expected %s/%q/%q",
+					d.Regla, d.Sugerencia, d.Correccion, tt.regla, tt.sugerencia, tt.correccion)
+			}
+		})
+	}
+}
+
+// TestAplicarSugerencias tests rewriting a sentence with several suggestions
+func TestAplicarSugerencias(t *testing.T) {
+	diagnosticos := DiagnosticarOracion("they was happy and goed home", ConTodosLosErrores())
+	if got, expected := AplicarSugerencias("they was happy and goed home", diagnosticos), "they were happy and went home"; got != expected {
+		t.Errorf("AplicarSugerencias() = %q, expected %q", got, expected)
+	}
+}
+
+// TestLemmaYPastOf tests the verb inflection lookups
+func TestLemmaYPastOf(t *testing.T) {
+	lemas := []struct {
+		palabra  string
+		expected string
+		existe   bool
+	}{
+		{"went", "go", true},
+		{"Goes", "go", true},
+		{"walking", "walk", true},
+		{"studied", "study", true},
+		{"read", "read", true},
+		{"football", "", false},
+	}
+	for _, tt := range lemas {
+		if base, existe := Lemma(tt.palabra); base != tt.expected || existe != tt.existe {
+			t.Errorf("Lemma(%q) = %q, %v, expected %q, %v", tt.palabra, base, existe, tt.expected, tt.existe)
+		}
+	}
+
+	pasados := []struct {
+		base     string
+		expected string
+		existe   bool
+	}{
+		{"go", "went", true},
+		{"stop", "stopped", true},
+		{"have", "had", true},
+		{"went", "", false},
+	}
+	for _, tt := range pasados {
+		if pasado, existe := PastOf(tt.base); pasado != tt.expected || existe != tt.existe {
+			t.Errorf("PastOf(%q) = %q, %v, expected %q, %v", tt.base, pasado, existe, tt.expected, tt.existe)
+		}
+	}
+}
+
+// TestTiempoVerbal tests tense mismatch diagnostics for present-tense verbs
+func TestTiempoVerbal(t *testing.T) {
+	tests := []struct {
+		name       string
+		oracion    string
+		mensaje    string
+		correccion string
+	}{
+		{
+			"base form with past time expression",
+			"I walk to school yesterday",
+			"The verb 'walk' is in the present tense, but 'yesterday' refers to the past. Use 'walked'.",
+			"I walked to school yesterday",
+		},
+		{
+			"third person form",
+			"She goes home",
+			"The verb 'goes' is not in the simple past. Use 'went'.",
+			"She went home",
+		},
+		{
+			"multi-word time expression",
+			"They eat pizza last night",
+			"The verb 'eat' is in the present tense, but 'last night' refers to the past. Use 'ate'.",
+			"They ate pizza last night",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion)
+			if len(diagnosticos) != 1 {
+				t.Fatalf("DiagnosticarOracion(%q) returned %d diagnostics, expected 1", tt.oracion, len(diagnosticos))
+			}
+
+			d := diagnosticos[0]
+			if d.Regla != ReglaTiempoVerbal || d.Mensaje != tt.mensaje || d.Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion() = %s/%q/%q, expected %s/%q/%q",
+					d.Regla, d.Mensaje, d.Correccion, ReglaTiempoVerbal, tt.mensaje, tt.correccion)
+			}
+		})
+	}
+}
+
+// TestDiagnosticarNegativo tests the negative simple past sentence type
+func TestDiagnosticarNegativo(t *testing.T) {
+	tests := []struct {
+		name       string
+		oracion    string
+		reglas     []string
+		correccion string
+	}{
+		{"valid did not", "I did not go to school", nil, ""},
+		{"valid didn't", "She didn't eat the cake yesterday", nil, ""},
+		{"valid with adverb", "They did not really like pizza", nil, ""},
+		{"same base and past form", "He did not read the book", nil, ""},
+		{"past form after didn't", "I didn't went home", []string{ReglaVerboBase}, "I didn't go home"},
+		{"third person after did not", "She did not goes home", []string{ReglaVerboBase}, "She did not go home"},
+		{"over-regularized after did not", "We did not eated pizza", []string{ReglaVerboBase}, "We did not eat pizza"},
+		{"missing negation", "I went home", []string{ReglaNegacionFaltante}, ""},
+		{"did without not", "I did go home", []string{ReglaNegacionFaltante}, ""},
+		{"missing verb", "I did not the ball", []string{ReglaVerboBaseFaltante}, ""},
+		{"missing subject", "did not go home", []string{ReglaSujetoFaltante}, ""},
+		{"other auxiliary", "I did not have is", []string{ReglaAuxiliar, ReglaAuxiliar}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion, ConPerfil(perfil(t, PerfilPasadoNegativo)), ConTodosLosErrores())
+
+			var reglas []string
+			for _, d := range diagnosticos {
+				reglas = append(reglas, d.Regla)
+			}
+			if !reflect.DeepEqual(reglas, tt.reglas) {
+				t.Fatalf("DiagnosticarOracion(%q) rules = %v, expected %v", tt.oracion, reglas, tt.reglas)
+			}
+			if tt.correccion != "" && diagnosticos[0].Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion(%q) correction = %q, expected %q", tt.oracion, diagnosticos[0].Correccion, tt.correccion)
+			}
+		})
+	}
+
+	if status, msg := ValidarOracion("I didn't go home", ConPerfil(perfil(t, PerfilPasadoNegativo))); status != "Valid" || msg != MensajeValidoNegativo {
+		t.Errorf("ValidarOracion() = %v, %v, expected Valid, %v", status, msg, MensajeValidoNegativo)
+	}
+}
+
+func TestDiagnosticarPregunta(t *testing.T) {
+	tests := []struct {
+		name       string
+		oracion    string
+		reglas     []string
+		correccion string
+	}{
+		{"yes/no with did", "Did you see the movie?", nil, ""},
+		{"wh-question with did", "Where did she go?", nil, ""},
+		{"was/were inversion", "Were they happy?", nil, ""},
+		{"wh-question with was", "Why was he late?", nil, ""},
+		{"subject question", "Who helped you?", nil, ""},
+		{"was/were agreement", "Was they happy?", []string{ReglaConcordanciaWasWere}, "Were they happy?"},
+		{"past form after did", "Did she went home?", []string{ReglaVerboBase}, "Did she go home?"},
+		{"missing question mark", "Did you see the movie", []string{ReglaSignoPregunta}, "Did you see the movie?"},
+		{"period instead of question mark", "Where did she go.", []string{ReglaSignoPregunta}, "Where did she go?"},
+		{"no inversion", "You saw the movie?", []string{ReglaInversionPregunta}, ""},
+		{"other auxiliary", "Do you like pizza?", []string{ReglaAuxiliar}, ""},
+		{"missing subject", "Did go home?", []string{ReglaSujetoFaltante}, ""},
+		{"missing verb", "Did you the movie?", []string{ReglaVerboBaseFaltante}, ""},
+		{"present subject question", "Who helps you?", []string{ReglaTiempoVerbal}, "Who helped you?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion, ConPerfil(perfil(t, PerfilPasadoPregunta)), ConTodosLosErrores())
+
+			var reglas []string
+			for _, d := range diagnosticos {
+				reglas = append(reglas, d.Regla)
+			}
+			if !reflect.DeepEqual(reglas, tt.reglas) {
+				t.Fatalf("DiagnosticarOracion(%q) rules = %v, expected %v", tt.oracion, reglas, tt.reglas)
+			}
+			if tt.correccion != "" && diagnosticos[0].Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion(%q) correction = %q, expected %q", tt.oracion, diagnosticos[0].Correccion, tt.correccion)
+			}
+		})
+	}
+
+	if status, msg := ValidarOracion("Did you go home?", ConPerfil(perfil(t, PerfilPasadoPregunta))); status != "Valid" || msg != MensajeValidoPregunta {
+		t.Errorf("ValidarOracion() = %v, %v, expected Valid, %v", status, msg, MensajeValidoPregunta)
+	}
+}
+
+func TestPerfiles(t *testing.T) {
+	var nombres []string
+	for _, p := range Perfiles() {
+		nombres = append(nombres, p.Nombre)
+	}
+	esperados := []string{PerfilPasadoAfirmativo, PerfilPasadoNegativo, PerfilPasadoPregunta, PerfilPresenteSimple, PerfilPasadoContinuo}
+	if !reflect.DeepEqual(nombres, esperados) {
+		t.Errorf("Perfiles() = %v, expected %v", nombres, esperados)
+	}
+
+	if p, err := BuscarPerfil(""); err != nil || p.Nombre != PerfilPasadoAfirmativo {
+		t.Errorf("BuscarPerfil(\"\") = %v, %v, expected the default profile", p.Nombre, err)
+	}
+	if p, err := BuscarPerfil(" Past-Negative "); err != nil || p.Nombre != PerfilPasadoNegativo {
+		t.Errorf("BuscarPerfil() = %v, %v, expected %v", p.Nombre, err, PerfilPasadoNegativo)
+	}
+	if _, err := BuscarPerfil("future-perfect"); err == nil {
+		t.Error("BuscarPerfil() expected an error for an unknown profile")
+	}
+
+	for _, p := range Perfiles() {
+		if got := ExplicacionValida(ConPerfil(p)); got != p.Explicacion {
+			t.Errorf("ExplicacionValida(%s) = %q, expected %q", p.Nombre, got, p.Explicacion)
+		}
+	}
+}
+
+func TestRegistrarPerfil(t *testing.T) {
+	registrados, indice := perfiles, maps.Clone(perfilesNombre)
+	t.Cleanup(func() { perfiles, perfilesNombre = registrados, indice })
+
+	// dosErrores reporta dos errores en cualquier oración
+	dosErrores := func(tokens []models.Token, d *Dictionary) []models.Diagnostic {
+		return []models.Diagnostic{
+			{Regla: "first", Severidad: models.SeveridadError},
+			{Regla: "second", Severidad: models.SeveridadError},
+		}
+	}
+	if err := RegistrarPerfil(NuevoPerfil(" Custom-Drill ", "Custom drill", "Custom explanation", dosErrores)); err != nil {
+		t.Fatalf("RegistrarPerfil() unexpected error = %v", err)
+	}
+
+	p := perfil(t, "custom-drill")
+	if n := len(DiagnosticarOracion("I went home.", ConPerfil(p))); n != 1 {
+		t.Errorf("first error mode returned %d diagnostics, expected 1", n)
+	}
+	if n := len(DiagnosticarOracion("I went home.", ConPerfil(p), ConTodosLosErrores())); n != 2 {
+		t.Errorf("all errors mode returned %d diagnostics, expected 2", n)
+	}
+
+	errores := []struct {
+		name   string
+		perfil Perfil
+	}{
+		{"duplicate name", NuevoPerfil("CUSTOM-DRILL", "", "", dosErrores)},
+		{"built-in name", NuevoPerfil(PerfilPasadoNegativo, "", "", dosErrores)},
+		{"empty name", NuevoPerfil("  ", "", "", dosErrores)},
+		{"no rules", Perfil{Nombre: "no-rules"}},
+	}
+	for _, tt := range errores {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegistrarPerfil(tt.perfil); err == nil {
+				t.Error("RegistrarPerfil() expected an error")
+			}
+		})
+	}
+}
+
+// TestMensajesSujetoPerfiles tests that every profile names a capitalized subject the same way
+func TestMensajesSujetoPerfiles(t *testing.T) {
+	tests := []struct {
+		perfil   string
+		oracion  string
+		expected string
+	}{
+		{PerfilPasadoAfirmativo, "They was happy.", "Incorrect verb form for 'they'. Use 'were'."},
+		{PerfilPasadoNegativo, "They wasn't happy.", "Incorrect verb form for 'they'. Use 'were'."},
+		{PerfilPasadoPregunta, "Was they happy?", "Incorrect verb form for 'they'. Use 'were'."},
+		{PerfilPasadoContinuo, "They was playing football.", "Incorrect verb form for 'they'. Use 'were'."},
+		{PerfilPasadoContinuo, "I were playing football.", "Incorrect verb form for 'I'. Use 'was'."},
+		{PerfilPresenteSimple, "He play football.", "The verb 'play' does not agree with 'he'. Use 'plays'."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.perfil+" "+tt.oracion, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion, ConPerfil(perfil(t, tt.perfil)))
+			if len(diagnosticos) == 0 || diagnosticos[0].Mensaje != tt.expected {
+				t.Errorf("DiagnosticarOracion(%q) = %+v, expected %q", tt.oracion, diagnosticos, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiagnosticarPerfiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		perfil     string
+		oracion    string
+		reglas     []string
+		correccion string
+	}{
+		{"present third person", PerfilPresenteSimple, "She plays football", nil, ""},
+		{"present plural", PerfilPresenteSimple, "They eat pizza", nil, ""},
+		{"present to be", PerfilPresenteSimple, "I am happy", nil, ""},
+		{"present noun phrase", PerfilPresenteSimple, "My parents work", nil, ""},
+		{"present agreement", PerfilPresenteSimple, "He play football", []string{ReglaConcordancia}, "He plays football"},
+		{"present plural agreement", PerfilPresenteSimple, "They plays football", []string{ReglaConcordancia}, "They play football"},
+		{"past in present", PerfilPresenteSimple, "She went to school", []string{ReglaTiempoVerbal}, "She goes to school"},
+		{"was in present", PerfilPresenteSimple, "They were happy", []string{ReglaTiempoVerbal}, "They are happy"},
+		{"missing present verb", PerfilPresenteSimple, "I the ball", []string{ReglaVerboPresenteFaltante}, ""},
+		{"present negative", PerfilPresenteSimple, "He doesn't play football", nil, ""},
+		{"present negative agreement", PerfilPresenteSimple, "He don't play football", []string{ReglaConcordancia}, "He does play football"},
+		{"past negative contraction in present", PerfilPresenteSimple, "I didn't go home.", []string{ReglaTiempoVerbal}, "I do not go home."},
+		{"past negative in present", PerfilPresenteSimple, "He didn't play football", []string{ReglaTiempoVerbal}, "He does not play football"},
+		{"did not in present", PerfilPresenteSimple, "They did not eat pizza", []string{ReglaTiempoVerbal}, "They do not eat pizza"},
+		{"past modal in present", PerfilPresenteSimple, "I could swim", []string{ReglaTiempoVerbal}, ""},
+		{"past continuous", PerfilPasadoContinuo, "I was playing football", nil, ""},
+		{"past continuous plural", PerfilPasadoContinuo, "They were eating pizza", nil, ""},
+		{"past continuous agreement", PerfilPasadoContinuo, "She were watching", []string{ReglaConcordanciaWasWere}, "She was watching"},
+		{"present continuous", PerfilPasadoContinuo, "We are walking", []string{ReglaTiempoVerbal}, "We were walking"},
+		{"past form after was", PerfilPasadoContinuo, "He was ran home", []string{ReglaGerundio}, "He was running home"},
+		{"missing was/were", PerfilPasadoContinuo, "I playing football", []string{ReglaWasWereFaltante}, ""},
+		{"missing gerund", PerfilPasadoContinuo, "They were the ball", []string{ReglaGerundioFaltante}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion, ConPerfil(perfil(t, tt.perfil)), ConTodosLosErrores())
+
+			var reglas []string
+			for _, d := range diagnosticos {
+				reglas = append(reglas, d.Regla)
+			}
+			if !reflect.DeepEqual(reglas, tt.reglas) {
+				t.Fatalf("DiagnosticarOracion(%q) rules = %v, expected %v", tt.oracion, reglas, tt.reglas)
+			}
+			if tt.correccion != "" && diagnosticos[0].Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion(%q) correction = %q, expected %q", tt.oracion, diagnosticos[0].Correccion, tt.correccion)
+			}
+		})
+	}
+}
+
+func TestContracciones(t *testing.T) {
+	tokens, err := AnalizarLexico("She wasn't happy")
+	if err != nil {
+		t.Fatalf("AnalizarLexico() unexpected error = %v", err)
+	}
+	esperados := []models.Token{
+		{Tipo: models.TipoSujeto, Texto: "she", Original: "She", Posicion: 0, ByteInicio: 0, ByteFin: 3, RunaInicio: 0, RunaFin: 3},
+		{Tipo: models.TipoDesconocido, Texto: "was", Original: "wasn't", Posicion: 1, ByteInicio: 4, ByteFin: 10, RunaInicio: 4, RunaFin: 10, Metadata: models.Metadata{EsContraccion: true}},
+		{Tipo: models.TipoDesconocido, Texto: "not", Posicion: 2, ByteInicio: 4, ByteFin: 10, RunaInicio: 4, RunaFin: 10, Metadata: models.Metadata{EsContraccion: true, PegadoAlAnterior: true}},
+		{Tipo: models.TipoAdjetivo, Texto: "happy", Original: "happy", Posicion: 3, ByteInicio: 11, ByteFin: 16, RunaInicio: 11, RunaFin: 16},
+	}
+	if !reflect.DeepEqual(tokens, esperados) {
+		t.Errorf("AnalizarLexico() = %+v, expected %+v", tokens, esperados)
+	}
+	if texto := textoTokens(tokens); texto != "She wasn't happy" {
+		t.Errorf("textoTokens() = %q, expected %q", texto, "She wasn't happy")
+	}
+
+	tests := []struct {
+		name       string
+		perfil     string
+		oracion    string
+		reglas     []string
+		correccion string
+	}{
+		{"didn't keeps its span", PerfilPasadoNegativo, "I didn't went home", []string{ReglaVerboBase}, "I didn't go home"},
+		{"curly apostrophe", PerfilPasadoNegativo, "She didn’t eat the cake", nil, ""},
+		{"wasn't is was plus not", PerfilPasadoNegativo, "She wasn't happy", nil, ""},
+		{"weren't agreement", PerfilPasadoNegativo, "He weren't happy", []string{ReglaConcordanciaWasWere}, "He wasn't happy"},
+		{"wasn't in affirmative", PerfilPasadoAfirmativo, "She wasn't happy", []string{ReglaNegacion}, ""},
+		{"negative question", PerfilPasadoPregunta, "Wasn't he late?", nil, ""},
+		{"present contraction", PerfilPresenteSimple, "I'm happy", nil, ""},
+		{"contraction in past continuous", PerfilPasadoContinuo, "I'm playing football", []string{ReglaTiempoVerbal}, "I was playing football"},
+		{"negative past continuous", PerfilPasadoContinuo, "They weren't watching", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion, ConPerfil(perfil(t, tt.perfil)), ConTodosLosErrores())
+
+			var reglas []string
+			for _, d := range diagnosticos {
+				reglas = append(reglas, d.Regla)
+			}
+			if !reflect.DeepEqual(reglas, tt.reglas) {
+				t.Fatalf("DiagnosticarOracion(%q) rules = %v, expected %v", tt.oracion, reglas, tt.reglas)
+			}
+			if tt.correccion != "" && diagnosticos[0].Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion(%q) correction = %q, expected %q", tt.oracion, diagnosticos[0].Correccion, tt.correccion)
+			}
+		})
+	}
+}
+
+func TestSegmentarOraciones(t *testing.T) {
+	tests := []struct {
+		name     string
+		texto    string
+		expected []string
+	}{
+		{"terminators", "I went home. Did you go? What a day! We slept", []string{"I went home.", "Did you go?", "What a day!", "We slept"}},
+		{"abbreviation and decimal", "Mr. Smith arrived at 5.30 yesterday. He left.", []string{"Mr. Smith arrived at 5.30 yesterday.", "He left."}},
+		{"initials and e.g.", "J. Smith ate fruit, e.g. apples. I ate it.", []string{"J. Smith ate fruit, e.g. apples.", "I ate it."}},
+		{"ellipsis", "I waited... and waited... Then he came.", []string{"I waited... and waited...", "Then he came."}},
+		{"grouped terminators", "You did what?! I knew it.", []string{"You did what?!", "I knew it."}},
+		{"closing quotes", `She said "I was tired." Then she left.`, []string{`She said "I was tired."`, "Then she left."}},
+		{"surrounding spaces", "  I played.  ", []string{"I played."}},
+		{"empty", "   ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var textos []string
+			for _, segmento := range SegmentarOraciones(tt.texto) {
+				if tt.texto[segmento.Inicio:segmento.Fin] != segmento.Texto {
+					t.Errorf("segment %+v does not match its offsets", segmento)
+				}
+				textos = append(textos, segmento.Texto)
+			}
+			if !reflect.DeepEqual(textos, tt.expected) {
+				t.Errorf("SegmentarOraciones(%q) = %q, expected %q", tt.texto, textos, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUbicarEnTexto(t *testing.T) {
+	texto := "I went home. They was happy."
+	segmento := SegmentarOraciones(texto)[1]
+
+	diagnosticos := DiagnosticarOracion(segmento.Texto)
+	UbicarEnTexto(diagnosticos, texto, segmento)
+
+	d := diagnosticos[0]
+	if got := texto[d.Rango.ByteInicio:d.Rango.ByteFin]; got != "was" {
+		t.Errorf("diagnostic range = %q, expected %q", got, "was")
+	}
+	if d.Rango.PalabraInicio != 4 {
+		t.Errorf("diagnostic word = %d, expected 4", d.Rango.PalabraInicio)
+	}
+	if d.Correccion != "I went home. They were happy." {
+		t.Errorf("correction = %q", d.Correccion)
+	}
+}
+
+func TestSepararPuntuacion(t *testing.T) {
+	tests := []struct {
+		name     string
+		oracion  string
+		expected []string
+	}{
+		{"comma", "Yesterday, I played", []string{"Yesterday", ",", "I", "played"}},
+		{"grouped terminators", "You did what?!", []string{"You", "did", "what", "?!"}},
+		{"quotes", `"I was tired."`, []string{`"`, "I", "was", "tired", ".", `"`}},
+		{"abbreviation and decimal", "Mr. Smith left at 5.30.", []string{"Mr.", "Smith", "left", "at", "5.30", "."}},
+		{"contraction", "I didn't go.", []string{"I", "didn't", "go", "."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := AnalizarLexico(tt.oracion)
+			if err != nil {
+				t.Fatalf("AnalizarLexico() unexpected error = %v", err)
+			}
+			var textos []string
+			for _, token := range tokens {
+				textos = append(textos, token.Original)
+				if esPuntuacion(token.Texto) && token.Tipo != models.TipoPuntuacion {
+					t.Errorf("token %q has type %v, expected TipoPuntuacion", token.Texto, token.Tipo)
+				}
+			}
+			if !reflect.DeepEqual(textos, tt.expected) {
+				t.Errorf("AnalizarLexico(%q) = %q, expected %q", tt.oracion, textos, tt.expected)
+			}
+			if texto := textoTokens(tokens); texto != tt.oracion {
+				t.Errorf("textoTokens() = %q, expected %q", texto, tt.oracion)
+			}
+		})
+	}
+}
+
+func TestPuntuacionEstricta(t *testing.T) {
+	tests := []struct {
+		name       string
+		perfil     string
+		oracion    string
+		reglas     []string
+		correccion string
+	}{
+		{"valid", PerfilPasadoAfirmativo, "I played football yesterday.", nil, ""},
+		{"comma after time expression", PerfilPasadoAfirmativo, "Yesterday, I played football.", nil, ""},
+		{"exclamation mark", PerfilPasadoAfirmativo, "We won the game!", nil, ""},
+		{"closing quote", PerfilPasadoAfirmativo, `"She ate pizza."`, nil, ""},
+		{"lowercase start", PerfilPasadoAfirmativo, "she ate pizza.", []string{ReglaMayuscula}, "She ate pizza."},
+		{"lowercase after quote", PerfilPasadoAfirmativo, `"she ate pizza."`, []string{ReglaMayuscula}, `"She ate pizza."`},
+		{"missing period", PerfilPasadoAfirmativo, "She ate pizza", []string{ReglaSignoFinal}, "She ate pizza."},
+		{"misplaced comma", PerfilPasadoAfirmativo, "She ate pizza,.", []string{ReglaPuntuacionMalColocada}, ""},
+		{"question uses its own rule", PerfilPasadoPregunta, "Did you go", []string{ReglaSignoPregunta}, "Did you go?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosticos := DiagnosticarOracion(tt.oracion, ConPerfil(perfil(t, tt.perfil)), ConPuntuacionEstricta(), ConTodosLosErrores())
+
+			var reglas []string
+			for _, d := range diagnosticos {
+				reglas = append(reglas, d.Regla)
+			}
+			if !reflect.DeepEqual(reglas, tt.reglas) {
+				t.Fatalf("DiagnosticarOracion(%q) rules = %v, expected %v", tt.oracion, reglas, tt.reglas)
+			}
+			if tt.correccion != "" && diagnosticos[0].Correccion != tt.correccion {
+				t.Errorf("DiagnosticarOracion(%q) correction = %q, expected %q", tt.oracion, diagnosticos[0].Correccion, tt.correccion)
+			}
+		})
+	}
+
+	if diagnosticos := DiagnosticarOracion("she ate pizza"); len(diagnosticos) != 0 {
+		t.Errorf("punctuation rules must be opt-in, got %v", diagnosticos)
+	}
+}
+
+func TestPosiciones(t *testing.T) {
+	oracion := "  Zoë  wasn’t   happy"
+	tokens, err := AnalizarLexico(oracion)
+	if err != nil {
+		t.Fatalf("AnalizarLexico() unexpected error = %v", err)
+	}
+
+	esperados := []struct {
+		texto               string
+		runaInicio, runaFin int
+	}{
+		{"Zoë", 2, 5},
+		{"wasn’t", 7, 13},
+		{"wasn’t", 7, 13}, // "not" comparte la posición de la contracción
+		{"happy", 16, 21},
+	}
+	if len(tokens) != len(esperados) {
+		t.Fatalf("AnalizarLexico() = %d tokens, expected %d", len(tokens), len(esperados))
+	}
+	for i, e := range esperados {
+		token := tokens[i]
+		if got := oracion[token.ByteInicio:token.ByteFin]; got != e.texto {
+			t.Errorf("token %d bytes point to %q, expected %q", i, got, e.texto)
+		}
+		if token.RunaInicio != e.runaInicio || token.RunaFin != e.runaFin {
+			t.Errorf("token %d runes = [%d, %d), expected [%d, %d)", i, token.RunaInicio, token.RunaFin, e.runaInicio, e.runaFin)
+		}
+	}
+
+	oracion = "“They  was happy”"
+	diagnosticos := DiagnosticarOracion(oracion)
+	if len(diagnosticos) != 1 {
+		t.Fatalf("DiagnosticarOracion(%q) = %v, expected one diagnostic", oracion, diagnosticos)
+	}
+	rango := diagnosticos[0].Rango
+	if got := oracion[rango.ByteInicio:rango.ByteFin]; got != "was" {
+		t.Errorf("diagnostic bytes point to %q, expected %q", got, "was")
+	}
+	if runas := []rune(oracion); string(runas[rango.RunaInicio:rango.RunaFin]) != "was" {
+		t.Errorf("diagnostic runes point to %q, expected %q", string(runas[rango.RunaInicio:rango.RunaFin]), "was")
+	}
+	if diagnosticos[0].Correccion != "“They  were happy”" {
+		t.Errorf("DiagnosticarOracion(%q) correction = %q", oracion, diagnosticos[0].Correccion)
+	}
+}
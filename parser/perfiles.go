@@ -0,0 +1,153 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"validar_oraciones/models"
+)
+
+// Identificadores de los perfiles de ejercicio incluidos
+const (
+	PerfilPasadoAfirmativo = "past-affirmative"
+	PerfilPasadoNegativo   = "past-negative"
+	PerfilPasadoPregunta   = "past-question"
+	PerfilPresenteSimple   = "present-simple"
+	PerfilPasadoContinuo   = "past-continuous"
+)
+
+// Perfil describe un ejercicio: la estructura que se valida y cómo se explica
+// una oración correcta
+type Perfil struct {
+	Nombre      string // Identificador estable usado en las peticiones
+	Descripcion string // Texto que se muestra en el selector de ejercicios
+	Explicacion string // Explicación de una oración válida
+	reglas      func(tokens []models.Token, v *validacion) []models.Diagnostic
+	signoPropio bool // Sus reglas ya validan el signo final (las preguntas exigen "?")
+}
+
+// ReglasPerfil valida los tokens de una oración con el diccionario recibido y
+// devuelve los problemas encontrados en orden. Si la petición pide solo el
+// primer error, los diagnósticos se cortan en el primero de severidad error.
+type ReglasPerfil func(tokens []models.Token, d *Dictionary) []models.Diagnostic
+
+// NuevoPerfil crea un perfil cuyas reglas se definen fuera del paquete, para
+// registrarlo con RegistrarPerfil. La puntuación estricta exige el signo final
+// como en los perfiles de oraciones afirmativas.
+func NuevoPerfil(nombre, descripcion, explicacion string, reglas ReglasPerfil) Perfil {
+	perfil := Perfil{Nombre: nombre, Descripcion: descripcion, Explicacion: explicacion}
+	if reglas != nil {
+		perfil.reglas = func(tokens []models.Token, v *validacion) []models.Diagnostic {
+			for _, diagnostico := range reglas(tokens, v.diccionario) {
+				if v.reportar(diagnostico) {
+					break
+				}
+			}
+			return v.diagnosticos
+		}
+	}
+	return perfil
+}
+
+var (
+	registro       sync.RWMutex      // Protege perfiles y perfilesNombre
+	perfiles       []Perfil          // En orden de registro
+	perfilesNombre map[string]Perfil // Índice por nombre
+)
+
+func init() {
+	incluidos := []Perfil{
+		{
+			Nombre:      PerfilPasadoAfirmativo,
+			Descripcion: "Simple past (affirmative)",
+			Explicacion: MensajeValido,
+			reglas:      diagnosticarAfirmativo,
+		},
+		{
+			Nombre:      PerfilPasadoNegativo,
+			Descripcion: "Simple past (negative)",
+			Explicacion: MensajeValidoNegativo,
+			reglas:      diagnosticarNegativo,
+		},
+		{
+			Nombre:      PerfilPasadoPregunta,
+			Descripcion: "Simple past (questions)",
+			Explicacion: MensajeValidoPregunta,
+			reglas:      diagnosticarPregunta,
+			signoPropio: true,
+		},
+		{
+			Nombre:      PerfilPresenteSimple,
+			Descripcion: "Simple present",
+			Explicacion: MensajeValidoPresente,
+			reglas:      diagnosticarPresente,
+		},
+		{
+			Nombre:      PerfilPasadoContinuo,
+			Descripcion: "Past continuous",
+			Explicacion: MensajeValidoContinuo,
+			reglas:      diagnosticarContinuo,
+		},
+	}
+	for _, perfil := range incluidos {
+		if err := RegistrarPerfil(perfil); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegistrarPerfil añade un perfil al registro. El nombre se guarda en
+// minúsculas, como lo busca BuscarPerfil; un nombre vacío o repetido o un
+// perfil sin reglas (creado sin NuevoPerfil) es un error.
+func RegistrarPerfil(perfil Perfil) error {
+	perfil.Nombre = strings.ToLower(strings.TrimSpace(perfil.Nombre))
+	if perfil.Nombre == "" {
+		return errors.New("a profile needs a name")
+	}
+	if perfil.reglas == nil {
+		return fmt.Errorf("profile %q has no rules", perfil.Nombre)
+	}
+
+	registro.Lock()
+	defer registro.Unlock()
+	if _, existe := perfilesNombre[perfil.Nombre]; existe {
+		return fmt.Errorf("profile %q is already registered", perfil.Nombre)
+	}
+	if perfilesNombre == nil {
+		perfilesNombre = make(map[string]Perfil)
+	}
+	perfiles = append(perfiles, perfil)
+	perfilesNombre[perfil.Nombre] = perfil
+	return nil
+}
+
+// Perfiles devuelve los perfiles disponibles en orden de registro
+func Perfiles() []Perfil {
+	registro.RLock()
+	defer registro.RUnlock()
+	return append([]Perfil(nil), perfiles...)
+}
+
+// PerfilPorDefecto devuelve el perfil que se usa cuando la petición no elige ninguno
+func PerfilPorDefecto() Perfil {
+	registro.RLock()
+	defer registro.RUnlock()
+	return perfilesNombre[PerfilPasadoAfirmativo]
+}
+
+// BuscarPerfil interpreta el perfil recibido en una petición; la cadena vacía
+// selecciona el perfil por defecto
+func BuscarPerfil(nombre string) (Perfil, error) {
+	nombre = strings.ToLower(strings.TrimSpace(nombre))
+	if nombre == "" {
+		return PerfilPorDefecto(), nil
+	}
+	registro.RLock()
+	perfil, existe := perfilesNombre[nombre]
+	registro.RUnlock()
+	if existe {
+		return perfil, nil
+	}
+	return PerfilPorDefecto(), fmt.Errorf("unknown exercise profile %q", nombre)
+}
@@ -0,0 +1,116 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"validar_oraciones/models"
+)
+
+// diagnosticarPregunta aplica las reglas de las preguntas en pasado simple:
+// [palabra interrogativa] + did + sujeto + verbo base ("Where did you go?"),
+// [palabra interrogativa] + was/were + sujeto ("Were they happy?") o, en las
+// preguntas de sujeto, who/what + verbo en pasado ("Who helped you?")
+func diagnosticarPregunta(tokens []models.Token, v *validacion) []models.Diagnostic {
+	if len(tokens) == 0 {
+		v.reportar(nuevoDiagnostico(ReglaSinTokens, "No tokens found.", tokens, 0, 0))
+		return v.diagnosticos
+	}
+
+	// El signo final se valida al terminar; el resto de reglas lo ignora
+	fin := len(tokens)
+	if tokens[fin-1].Tipo == models.TipoPuntuacion {
+		fin--
+	}
+
+	inicio := 0
+	if tokens[0].Tipo == models.TipoInterrogativo {
+		inicio = 1
+	}
+
+	if inicio < fin && diagnosticarEstructuraPregunta(tokens, inicio, fin, v) {
+		return v.diagnosticos
+	}
+	if inicio == fin {
+		if v.reportar(nuevoDiagnostico(ReglaInversionPregunta,
+			"A question needs 'did', 'was' or 'were' before the subject.", tokens, 0, fin)) {
+			return v.diagnosticos
+		}
+	}
+
+	if fin == len(tokens) || tokens[fin].Texto != "?" {
+		v.reportar(diagnosticoSignoPregunta(tokens, fin))
+	}
+
+	return v.diagnosticos
+}
+
+// diagnosticarEstructuraPregunta valida el auxiliar, el sujeto y el verbo que
+// siguen a la palabra interrogativa; devuelve true si la validación debe detenerse
+func diagnosticarEstructuraPregunta(tokens []models.Token, inicio, fin int, v *validacion) bool {
+	auxiliar := tokens[inicio]
+
+	switch {
+	case auxiliar.Texto == "did" || auxiliar.Tipo == models.TipoNegativo:
+	case auxiliar.Texto == "was" || auxiliar.Texto == "were":
+	case inicio == 1 && (tokens[0].Texto == "who" || tokens[0].Texto == "what") && esVerbo(auxiliar):
+		// Pregunta de sujeto: la palabra interrogativa hace de sujeto
+		if esPresente(auxiliar) {
+			return v.reportar(diagnosticoTiempoVerbal(v.diccionario, tokens, inicio, buscarExpresionPasada(tokens)))
+		}
+		return false
+	case auxiliaresNoPermitidos[auxiliar.Texto]:
+		return v.reportar(nuevoDiagnostico(ReglaAuxiliar,
+			fmt.Sprintf("Simple past questions use 'did', 'was' or 'were', not '%s'.", auxiliar.Texto),
+			tokens, inicio, inicio+1))
+	default:
+		return v.reportar(nuevoDiagnostico(ReglaInversionPregunta,
+			"A question needs 'did', 'was' or 'were' before the subject.", tokens, inicio, inicio+1))
+	}
+
+	// The negation of "wasn't" or "weren't" comes before the subject
+	sujeto := inicio + 1
+	if sujeto < fin && esContinuacion(tokens[sujeto]) && tokens[sujeto].Texto == "not" {
+		sujeto++
+	}
+	if sujeto == fin || tokens[sujeto].Tipo != models.TipoSujeto {
+		return v.reportar(nuevoDiagnostico(ReglaSujetoFaltante,
+			fmt.Sprintf("The subject must follow '%s'.", auxiliar.Texto), tokens, inicio, min(sujeto+1, fin)))
+	}
+
+	if auxiliar.Texto == "was" || auxiliar.Texto == "were" {
+		pronombre, verbo, existe := verboWasWere(tokens[sujeto].Texto)
+		if existe && verbo != auxiliar.Texto {
+			return v.reportar(diagnosticoFormaSer(tokens, inicio, ReglaConcordanciaWasWere,
+				fmt.Sprintf("Incorrect verb form for '%s'. Use '%s'.", nombreSujeto(pronombre), verbo), verbo))
+		}
+		return false
+	}
+
+	// After "did" and the subject comes the verb, optionally after an adverb
+	verbo := sujeto + 1
+	for verbo < fin && tokens[verbo].Tipo == models.TipoAdverbio {
+		verbo++
+	}
+	if verbo == fin || !esVerbo(tokens[verbo]) {
+		return v.reportar(nuevoDiagnostico(ReglaVerboBaseFaltante,
+			fmt.Sprintf("A verb in its base form is missing after '%s %s'.", auxiliar.Texto, tokens[sujeto].Texto),
+			tokens, inicio, sujeto+1))
+	}
+
+	if diagnostico, existe := diagnosticoVerboBase(v.diccionario, tokens, inicio, verbo); existe {
+		return v.reportar(diagnostico)
+	}
+	return false
+}
+
+// diagnosticoSignoPregunta pide el signo de interrogación final; fin es la
+// posición del signo actual o el número de tokens si la oración no tiene signo.
+// La sugerencia reescribe la última palabra junto con su signo.
+func diagnosticoSignoPregunta(tokens []models.Token, fin int) models.Diagnostic {
+	desde, hasta := max(fin-1, 0), min(fin+1, len(tokens))
+
+	diagnostico := nuevoDiagnostico(ReglaSignoPregunta,
+		"A question must end with a question mark.", tokens, desde, hasta)
+	diagnostico.Sugerencia = strings.TrimRight(textoTokens(tokens[desde:hasta]), terminadores) + "?"
+	return diagnostico
+}
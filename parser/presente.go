@@ -0,0 +1,143 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"validar_oraciones/models"
+)
+
+// diagnosticarPresente aplica las reglas del presente simple: sujeto + verbo en
+// forma base o en tercera persona (o am/is/are) + complemento. La negación con
+// do/does ("he doesn't play") concuerda en el auxiliar; "didn't" y los modales
+// en pasado son de otro tiempo.
+func diagnosticarPresente(tokens []models.Token, v *validacion) []models.Diagnostic {
+	if len(tokens) == 0 {
+		v.reportar(nuevoDiagnostico(ReglaSinTokens, "No tokens found.", tokens, 0, 0))
+		return v.diagnosticos
+	}
+
+	sujeto, verbo := -1, -1
+	for i, token := range tokens {
+		switch {
+		case token.Tipo == models.TipoSujeto && sujeto == -1:
+			sujeto = i
+		case verbo == -1 && (esVerbo(token) || esFormaSer(token.Texto) || token.Tipo == models.TipoVerboAuxiliar ||
+			token.Tipo == models.TipoNegativo || token.Tipo == models.TipoVerboModalPasado):
+			verbo = i
+		}
+	}
+
+	if sujeto == -1 {
+		if v.reportar(nuevoDiagnostico(ReglaSujetoFaltante,
+			"The subject is missing in the sentence.", tokens, 0, len(tokens))) {
+			return v.diagnosticos
+		}
+	}
+
+	if verbo == -1 {
+		v.reportar(nuevoDiagnostico(ReglaVerboPresenteFaltante,
+			"A present tense verb is missing in the sentence.", tokens, 0, len(tokens)))
+		return v.diagnosticos
+	}
+
+	if sujeto > verbo {
+		if v.reportar(nuevoDiagnostico(ReglaOrdenSujetoVerbo,
+			"The verb must follow the subject.", tokens, verbo, verbo+1)) {
+			return v.diagnosticos
+		}
+	}
+
+	// Ensure complements come after the verb
+	for i := 0; i < verbo; i++ {
+		if tokens[i].Tipo == models.TipoComplemento {
+			if v.reportar(nuevoDiagnostico(ReglaComplementoAntesDelVerbo,
+				"The complement must come after the verb.", tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+			break
+		}
+	}
+
+	if diagnostico, existe := diagnosticoPresente(v.diccionario, tokens, sujeto, verbo); existe {
+		v.reportar(diagnostico)
+	}
+
+	return v.diagnosticos
+}
+
+// diagnosticoPresente comprueba que el verbo esté en presente y concuerde con
+// el sujeto ("he plays", "they play", "she is"); sujeto es -1 si no hay sujeto
+func diagnosticoPresente(d *Dictionary, tokens []models.Token, sujeto, verbo int) (models.Diagnostic, bool) {
+	token := tokens[verbo]
+	sujetoTexto := "it" // Sin sujeto solo se comprueba el tiempo verbal
+	if sujeto != -1 {
+		sujetoTexto = tokens[sujeto].Texto
+	}
+
+	switch token.Tipo {
+	case models.TipoNegativo:
+		// "didn't go" es el pasado negativo; el presente niega con do/does
+		esperado := "do not"
+		if esTerceraPersonaSingular(sujetoTexto) {
+			esperado = "does not"
+		}
+		diagnostico := nuevoDiagnostico(ReglaTiempoVerbal,
+			fmt.Sprintf("'%s' is not in the simple present. Use '%s'.", textoToken(token), esperado), tokens, verbo, verbo+1)
+		diagnostico.Sugerencia = esperado
+		return diagnostico, true
+	case models.TipoVerboModalPasado:
+		return nuevoDiagnostico(ReglaTiempoVerbal,
+			fmt.Sprintf("The modal verb '%s' is not in the simple present. Use the main verb without it.", textoToken(token)), tokens, verbo, verbo+1), true
+	}
+
+	var esperado string
+	pasado := false
+	if esFormaSer(token.Texto) {
+		esperado = formaSer(sujetoTexto, false)
+		pasado = token.Texto == "was" || token.Texto == "were"
+	} else {
+		lema := token.Metadata.Lema
+		if corregido, existe := d.formaSobrerregularizada(token.Texto); existe {
+			lema, _ = d.Lemma(corregido)
+			pasado = true
+		}
+
+		formas, existe := d.FormasDe(lema)
+		switch {
+		case existe && esTerceraPersonaSingular(sujetoTexto):
+			esperado = formas.TerceraPersona
+		case existe:
+			esperado = formas.Base
+		}
+
+		// "read", "put" o "cut" comparten la forma base y el pasado
+		if token.Metadata.Forma == models.FormaPasado && token.Texto != formas.Base {
+			pasado = true
+		}
+		if lema == "" && strings.HasSuffix(token.Texto, "ed") {
+			pasado = true
+		}
+	}
+
+	var regla, mensaje string
+	switch {
+	case pasado && esperado == "":
+		return nuevoDiagnostico(ReglaTiempoVerbal,
+			fmt.Sprintf("The verb '%s' is not in the simple present.", token.Texto), tokens, verbo, verbo+1), true
+	case pasado:
+		regla = ReglaTiempoVerbal
+		mensaje = fmt.Sprintf("The verb '%s' is not in the simple present. Use '%s'.", token.Texto, esperado)
+	case sujeto != -1 && esperado != "" && token.Texto != esperado:
+		regla = ReglaConcordancia
+		mensaje = fmt.Sprintf("The verb '%s' does not agree with '%s'. Use '%s'.", token.Texto, nombreSujeto(textoToken(tokens[sujeto])), esperado)
+	default:
+		return models.Diagnostic{}, false
+	}
+
+	if esFormaSer(token.Texto) {
+		return diagnosticoFormaSer(tokens, verbo, regla, mensaje, esperado), true
+	}
+	diagnostico := nuevoDiagnostico(regla, mensaje, tokens, verbo, verbo+1)
+	diagnostico.Sugerencia = esperado
+	return diagnostico, true
+}
@@ -0,0 +1,104 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"validar_oraciones/models"
+)
+
+// diagnosticarPuntuacion aplica las reglas de puntuación estricta: mayúscula en
+// la primera palabra, signo final y signos mal colocados (",," o ",.")
+func diagnosticarPuntuacion(tokens []models.Token, v *validacion) []models.Diagnostic {
+	primera, ultima := -1, -1
+	for i, token := range tokens {
+		if token.Tipo != models.TipoPuntuacion {
+			if primera == -1 {
+				primera = i
+			}
+			ultima = i
+		}
+	}
+	if primera == -1 {
+		return v.diagnosticos
+	}
+
+	// The first word starts with a capital letter
+	if texto := textoToken(tokens[primera]); !empiezaConMayuscula(texto) {
+		if v.reportar(diagnosticoPalabra(tokens, primera, ReglaMayuscula,
+			"The first word of the sentence must start with a capital letter.", mayusculaInicial(texto))) {
+			return v.diagnosticos
+		}
+	}
+
+	// The sentence ends with ".", "!" or "?", optionally followed by closing quotes
+	if !v.opciones.perfil.signoPropio && !terminaConSigno(tokens[ultima+1:]) {
+		if v.reportar(diagnosticoPalabra(tokens, ultima, ReglaSignoFinal,
+			"The sentence must end with a period, a question mark or an exclamation mark.", textoToken(tokens[ultima])+".")) {
+			return v.diagnosticos
+		}
+	}
+
+	// Punctuation never opens the sentence nor follows a comma, semicolon or colon
+	for i, token := range tokens {
+		if token.Tipo != models.TipoPuntuacion || strings.ContainsAny(token.Texto, signosApertura) {
+			continue
+		}
+		if i < primera || (i > 0 && strings.ContainsAny(tokens[i-1].Texto, ",;:") && tokens[i-1].Tipo == models.TipoPuntuacion) {
+			if v.reportar(nuevoDiagnostico(ReglaPuntuacionMalColocada,
+				fmt.Sprintf("Unexpected punctuation mark '%s'.", token.Texto), tokens, i, i+1)) {
+				return v.diagnosticos
+			}
+		}
+	}
+
+	return v.diagnosticos
+}
+
+// terminaConSigno indica si los tokens que siguen a la última palabra contienen
+// un signo final
+func terminaConSigno(cierre []models.Token) bool {
+	for _, token := range cierre {
+		if strings.ContainsAny(token.Texto, terminadores+"…") {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnosticoPalabra crea un diagnóstico sobre el token i que sugiere reemplazarlo
+// por otro texto; el rango abarca toda la palabra escrita a la que pertenece
+// (los signos pegados incluidos) para que la sugerencia no los pierda
+func diagnosticoPalabra(tokens []models.Token, i int, regla, mensaje, reemplazo string) models.Diagnostic {
+	desde, hasta := i, i+1
+	for desde > 0 && tokens[desde].Metadata.PegadoAlAnterior {
+		desde--
+	}
+	for hasta < len(tokens) && tokens[hasta].Metadata.PegadoAlAnterior {
+		hasta++
+	}
+
+	palabra := make([]models.Token, hasta-desde)
+	copy(palabra, tokens[desde:hasta])
+	palabra[i-desde].Original = reemplazo
+
+	diagnostico := nuevoDiagnostico(regla, mensaje, tokens, desde, hasta)
+	diagnostico.Sugerencia = textoTokens(palabra)
+	return diagnostico
+}
+
+// empiezaConMayuscula indica si la primera letra del texto es mayúscula
+func empiezaConMayuscula(texto string) bool {
+	inicial, _ := utf8.DecodeRuneInString(texto)
+	return !unicode.IsLetter(inicial) || unicode.IsUpper(inicial)
+}
+
+// mayusculaInicial escribe la primera letra del texto en mayúscula
+func mayusculaInicial(texto string) string {
+	if texto == "" {
+		return texto
+	}
+	inicial, tamano := utf8.DecodeRuneInString(texto)
+	return string(unicode.ToUpper(inicial)) + texto[tamano:]
+}
@@ -0,0 +1,195 @@
+package validators
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"validar_oraciones/models"
+)
+
+// abreviaturas que terminan en punto sin cerrar la oración ("Mr. Smith")
+var abreviaturas = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true,
+	"st": true, "mt": true, "vs": true, "etc": true, "e.g": true, "i.e": true,
+	"a.m": true, "p.m": true, "u.s": true, "u.k": true, "jan": true, "feb": true,
+	"mar": true, "apr": true, "jun": true, "jul": true, "aug": true, "sep": true, "sept": true,
+	"oct": true, "nov": true, "dec": true,
+}
+
+// SegmentarOraciones divide el texto en oraciones terminadas en ".", "!", "?" o
+// puntos suspensivos. No corta en abreviaturas ("Dr."), iniciales ("J. Smith"),
+// decimales ("5.30") ni en puntos suspensivos seguidos de minúscula, y deja
+// dentro de la oración las comillas y paréntesis que cierran tras el signo.
+// Los rangos de cada segmento apuntan a bytes del texto recibido.
+func SegmentarOraciones(texto string) []models.Segmento {
+	var segmentos []models.Segmento
+	inicio := -1
+
+	for i := 0; i < len(texto); {
+		r, tamano := utf8.DecodeRuneInString(texto[i:])
+		if !esTerminador(r) {
+			if inicio == -1 && !unicode.IsSpace(r) {
+				inicio = i
+			}
+			i += tamano
+			continue
+		}
+		if inicio == -1 {
+			inicio = i // Signos sueltos al comienzo
+		}
+
+		// Consumir el grupo de signos ("?!", "...") y los cierres que lo siguen
+		fin := i
+		for fin < len(texto) {
+			r, tamano := utf8.DecodeRuneInString(texto[fin:])
+			if !esTerminador(r) {
+				break
+			}
+			fin += tamano
+		}
+		signos := texto[i:fin]
+		for fin < len(texto) {
+			r, tamano := utf8.DecodeRuneInString(texto[fin:])
+			if !strings.ContainsRune(`"')]”’»`, r) {
+				break
+			}
+			fin += tamano
+		}
+
+		if cierraOracion(texto, inicio, i, signos, fin) {
+			segmentos = append(segmentos, models.Segmento{Texto: texto[inicio:fin], Inicio: inicio, Fin: fin})
+			inicio = -1
+		}
+		i = fin
+	}
+
+	if inicio != -1 {
+		fin := len(strings.TrimRightFunc(texto, unicode.IsSpace))
+		segmentos = append(segmentos, models.Segmento{Texto: texto[inicio:fin], Inicio: inicio, Fin: fin})
+	}
+
+	return segmentos
+}
+
+// esTerminador indica si el carácter puede cerrar una oración
+func esTerminador(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+// cierraOracion decide si los signos texto[signo:fin] terminan la oración que
+// empieza en inicio
+func cierraOracion(texto string, inicio, signo int, signos string, fin int) bool {
+	if fin == len(texto) {
+		return true
+	}
+
+	// Dentro de una palabra o número ("5.30", "U.S.A") nunca se corta
+	siguiente, _ := utf8.DecodeRuneInString(texto[fin:])
+	if !unicode.IsSpace(siguiente) {
+		return false
+	}
+
+	// Tras unos puntos suspensivos la oración sigue si continúa en minúscula
+	if signos == "…" || strings.HasPrefix(signos, "..") {
+		resto := strings.TrimLeftFunc(texto[fin:], unicode.IsSpace)
+		primera, _ := utf8.DecodeRuneInString(resto)
+		return resto == "" || !unicode.IsLower(primera)
+	}
+
+	if signos != "." {
+		return true
+	}
+
+	// La palabra que precede al punto decide si es una abreviatura o una inicial
+	palabra := texto[inicio:signo]
+	if espacio := strings.LastIndexFunc(palabra, unicode.IsSpace); espacio != -1 {
+		palabra = palabra[espacio+1:]
+	}
+	palabra = strings.TrimLeft(palabra, `"'([“‘«`)
+	if utf8.RuneCountInString(palabra) == 1 && palabra != "I" {
+		inicial, _ := utf8.DecodeRuneInString(palabra)
+		return !unicode.IsUpper(inicial)
+	}
+	return !abreviaturas[strings.ToLower(palabra)]
+}
+
+// UbicarEnTexto traslada los rangos de los diagnósticos de una oración al texto
+// completo del que salió el segmento y recalcula sus correcciones sobre ese texto
+func UbicarEnTexto(diagnosticos []models.Diagnostic, texto string, segmento models.Segmento) {
+	palabrasPrevias := len(strings.Fields(texto[:segmento.Inicio]))
+	runasPrevias := utf8.RuneCountInString(texto[:segmento.Inicio])
+	for i := range diagnosticos {
+		rango := &diagnosticos[i].Rango
+		rango.PalabraInicio += palabrasPrevias
+		rango.PalabraFin += palabrasPrevias
+		rango.ByteInicio += segmento.Inicio
+		rango.ByteFin += segmento.Inicio
+		rango.RunaInicio += runasPrevias
+		rango.RunaFin += runasPrevias
+	}
+	completarCorrecciones(diagnosticos, texto)
+}
+
+// UbicarTokensEnTexto traslada las posiciones de los tokens de una oración al
+// texto completo del que salió el segmento
+func UbicarTokensEnTexto(tokens []models.Token, texto string, segmento models.Segmento) {
+	runasPrevias := utf8.RuneCountInString(texto[:segmento.Inicio])
+	for i := range tokens {
+		tokens[i].ByteInicio += segmento.Inicio
+		tokens[i].ByteFin += segmento.Inicio
+		tokens[i].RunaInicio += runasPrevias
+		tokens[i].RunaFin += runasPrevias
+	}
+}
+
+// ReubicarEnOriginal traslada los rangos de unos diagnósticos calculados sobre un
+// texto limpio al texto original del que se obtuvo; origen[i] es la posición en
+// el original del byte i del texto limpio. Las correcciones no cambian: siguen
+// escritas sobre el texto limpio.
+func ReubicarEnOriginal(diagnosticos []models.Diagnostic, original string, origen []int) {
+	for i := range diagnosticos {
+		rango := &diagnosticos[i].Rango
+		inicio, fin, ok := posicionOriginal(rango.ByteInicio, rango.ByteFin, original, origen)
+		if !ok {
+			continue
+		}
+
+		rango.ByteInicio, rango.ByteFin = inicio, fin
+		rango.PalabraInicio = len(strings.Fields(original[:inicio]))
+		rango.PalabraFin = rango.PalabraInicio + len(strings.Fields(original[inicio:fin]))
+		completarRunas(rango, original)
+	}
+}
+
+// ReubicarTokensEnOriginal hace lo mismo que ReubicarEnOriginal con las
+// posiciones de los tokens
+func ReubicarTokensEnOriginal(tokens []models.Token, original string, origen []int) {
+	for i := range tokens {
+		token := &tokens[i]
+		inicio, fin, ok := posicionOriginal(token.ByteInicio, token.ByteFin, original, origen)
+		if !ok {
+			continue
+		}
+
+		token.ByteInicio, token.ByteFin = inicio, fin
+		token.RunaInicio = utf8.RuneCountInString(original[:inicio])
+		token.RunaFin = token.RunaInicio + utf8.RuneCountInString(original[inicio:fin])
+	}
+}
+
+// posicionOriginal traduce los bytes [inicio, fin) del texto limpio a bytes del original
+func posicionOriginal(inicio, fin int, original string, origen []int) (int, int, bool) {
+	if inicio < 0 || fin > len(origen) || inicio > fin {
+		return 0, 0, false
+	}
+
+	desde := len(original)
+	if inicio < len(origen) {
+		desde = origen[inicio]
+	}
+	hasta := desde
+	if fin > inicio {
+		hasta = origen[fin-1] + 1
+	}
+	return desde, hasta, true
+}
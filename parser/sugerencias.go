@@ -0,0 +1,105 @@
+package validators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"validar_oraciones/models"
+)
+
+// formaSobrerregularizada detecta pasados formados con "-ed" sobre un verbo
+// irregular ("goed", "eated", "swimmed") y devuelve el pasado correcto
+func (d *Dictionary) formaSobrerregularizada(palabra string) (string, bool) {
+	palabra = strings.ToLower(palabra)
+	if !strings.HasSuffix(palabra, "ed") || d.existe(palabra) {
+		return "", false
+	}
+
+	raiz := strings.TrimSuffix(palabra, "ed")
+	candidatos := []string{raiz, strings.TrimSuffix(palabra, "d")}
+	if n := len(raiz); n > 1 && raiz[n-1] == raiz[n-2] {
+		candidatos = append(candidatos, raiz[:n-1]) // swimmed -> swim
+	}
+	if strings.HasSuffix(raiz, "i") {
+		candidatos = append(candidatos, strings.TrimSuffix(raiz, "i")+"y") // flied -> fly
+	}
+
+	for _, base := range candidatos {
+		if pasado, existe := d.PastOf(base); existe && d.esPasadoIrregular(pasado) {
+			return pasado, true
+		}
+	}
+	return "", false
+}
+
+// diagnosticoPasadoIrregular crea el diagnóstico para un pasado sobrerregularizado
+func diagnosticoPasadoIrregular(tokens []models.Token, i int, pasado string) models.Diagnostic {
+	diagnostico := nuevoDiagnostico(ReglaPasadoIrregular,
+		fmt.Sprintf("'%s' is not a valid past form. Use '%s'.", tokens[i].Texto, pasado),
+		tokens, i, i+1)
+	diagnostico.Sugerencia = pasado
+	return diagnostico
+}
+
+// AplicarSugerencias reescribe el texto reemplazando el rango de cada diagnóstico
+// con sugerencia; los rangos en bytes deben apuntar a ese mismo texto
+func AplicarSugerencias(texto string, diagnosticos []models.Diagnostic) string {
+	var cambios []models.Diagnostic
+	for _, d := range diagnosticos {
+		if d.Sugerencia != "" && d.Rango.ByteInicio <= d.Rango.ByteFin && d.Rango.ByteFin <= len(texto) {
+			cambios = append(cambios, d)
+		}
+	}
+
+	// Aplicar de atrás hacia adelante para no desplazar los rangos pendientes
+	sort.SliceStable(cambios, func(i, j int) bool {
+		return cambios[i].Rango.ByteInicio > cambios[j].Rango.ByteInicio
+	})
+
+	limite := len(texto)
+	for _, d := range cambios {
+		if d.Rango.ByteFin > limite {
+			continue // Se solapa con un cambio ya aplicado
+		}
+		texto = texto[:d.Rango.ByteInicio] + d.Sugerencia + texto[d.Rango.ByteFin:]
+		limite = d.Rango.ByteInicio
+	}
+
+	return texto
+}
+
+// completarCorrecciones guarda en cada diagnóstico con sugerencia la oración reescrita
+func completarCorrecciones(diagnosticos []models.Diagnostic, texto string) {
+	for i := range diagnosticos {
+		if diagnosticos[i].Sugerencia != "" {
+			diagnosticos[i].Correccion = AplicarSugerencias(texto, diagnosticos[i:i+1])
+		}
+	}
+}
+
+// igualarMayuscula escribe la sugerencia con mayúscula inicial si el texto que
+// reemplaza la tiene ("Was they" -> "Were they")
+func igualarMayuscula(original, sugerencia string) string {
+	if inicial, _ := utf8.DecodeRuneInString(original); unicode.IsUpper(inicial) {
+		return mayusculaInicial(sugerencia)
+	}
+	return sugerencia
+}
+
+// textoTokens reconstruye la oración uniendo los tokens con un espacio, el mismo
+// texto sobre el que rangoTokens calcula los bytes
+func textoTokens(tokens []models.Token) string {
+	var texto strings.Builder
+	for i, token := range tokens {
+		if i > 0 && esContinuacion(token) {
+			continue // Su texto ya está en la contracción
+		}
+		if i > 0 && !token.Metadata.PegadoAlAnterior {
+			texto.WriteByte(' ')
+		}
+		texto.WriteString(textoToken(token))
+	}
+	return texto.String()
+}
@@ -0,0 +1,50 @@
+package validators
+
+import "validar_oraciones/models"
+
+// porDefecto es el validador que usan las funciones del paquete: el
+// diccionario cargado con CargarDiccionario y sin opciones propias
+var porDefecto = &Validator{}
+
+// Validator valida oraciones con un diccionario y unas opciones fijas. Varios
+// validadores con diccionarios u opciones distintas pueden usarse a la vez;
+// las funciones del paquete usan uno por defecto.
+type Validator struct {
+	diccionario *Dictionary // nil: el diccionario del paquete, que puede recargarse
+	opciones    []Opcion
+}
+
+// NewValidator crea un validador con el diccionario y las opciones indicadas.
+// Con un diccionario nil usa el del paquete y ve sus recargas. Las opciones de
+// cada llamada se aplican después de las del validador.
+func NewValidator(d *Dictionary, opciones ...Opcion) *Validator {
+	return &Validator{diccionario: d, opciones: opciones}
+}
+
+// ValidadorActual devuelve un validador fijo al diccionario en uso en este
+// momento. Una petición que analiza y diagnostica el mismo texto lo usa para
+// que una recarga entre ambos pasos no mezcle dos versiones del diccionario.
+func ValidadorActual() *Validator {
+	return NewValidator(diccionarioActual())
+}
+
+// Diccionario devuelve el diccionario con el que valida en este momento
+func (v *Validator) Diccionario() *Dictionary {
+	if v.diccionario != nil {
+		return v.diccionario
+	}
+	return diccionarioActual()
+}
+
+// con devuelve las opciones del validador seguidas de las de la llamada
+func (v *Validator) con(opciones []Opcion) []Opcion {
+	if len(v.opciones) == 0 {
+		return opciones
+	}
+	return append(v.opciones[:len(v.opciones):len(v.opciones)], opciones...)
+}
+
+// BuscarPalabra consulta una palabra o expresión en el diccionario del validador
+func (v *Validator) BuscarPalabra(palabra string) (models.Palabra, bool) {
+	return v.Diccionario().BuscarPalabra(palabra)
+}
@@ -0,0 +1,110 @@
+package validators
+
+import (
+	"testing"
+	"validar_oraciones/models"
+)
+
+// vocabularioRobots es un diccionario mínimo, distinto del de words.json
+func vocabularioRobots(t *testing.T) *Dictionary {
+	t.Helper()
+	var wordsData WordsData
+	wordsData.Sujeto = []string{"robot"}
+	wordsData.Verbos.Regulares = []string{"beeped"}
+	wordsData.Verbos.Formas = []FormasVerbo{{Base: "beep", Pasado: "beeped"}}
+	d, err := NewDictionary(wordsData)
+	if err != nil {
+		t.Fatalf("NewDictionary() unexpected error = %v", err)
+	}
+	return d
+}
+
+func TestNewDictionary(t *testing.T) {
+	if d, err := NewDictionary(WordsData{}); err == nil || d != nil {
+		t.Errorf("NewDictionary(empty) = %v, %v, expected an error", d, err)
+	}
+
+	d := vocabularioRobots(t)
+	if _, existe := d.BuscarPalabra("robot"); !existe {
+		t.Error("the custom word is not in the dictionary")
+	}
+	if pasado, existe := d.PastOf("beep"); !existe || pasado != "beeped" {
+		t.Errorf("PastOf(beep) = %q, %v, expected beeped", pasado, existe)
+	}
+	if _, existe := BuscarPalabra("robot"); existe {
+		t.Error("a dictionary built with NewDictionary changed the package dictionary")
+	}
+}
+
+// TestValidatorsSideBySide tests that validators with different dictionaries
+// and options coexist with the package functions
+func TestValidatorsSideBySide(t *testing.T) {
+	completo, err := LoadDictionary("../words.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	robots := NewValidator(vocabularioRobots(t))
+	estandar := NewValidator(completo)
+	estricto := NewValidator(nil, ConPuntuacionEstricta())
+
+	if palabra := robots.ClasificarPalabra("robot", models.Contexto{}); palabra.Tipo != models.TipoSujeto {
+		t.Errorf("robots.ClasificarPalabra(robot) = %v, expected a subject", palabra.Tipo)
+	}
+	if palabra := estandar.ClasificarPalabra("robot", models.Contexto{}); palabra.Tipo == models.TipoSujeto {
+		t.Error("the words.json validator knows the custom subject")
+	}
+
+	tests := []struct {
+		name      string
+		validator *Validator
+		oracion   string
+		expected  string
+	}{
+		{"custom vocabulary", robots, "Robot beeped.", "Valid"},
+		{"custom vocabulary lacks words.json", robots, "They went home.", "Invalid"},
+		{"words.json dictionary", estandar, "I played football.", "Valid"},
+		{"words.json lacks the custom vocabulary", estandar, "robot beeped", "Invalid"},
+		{"validator options", estricto, "I played football", "Invalid"},
+		{"default validator", porDefecto, "I played football", "Valid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resultado, explicacion := tt.validator.ValidarOracion(tt.oracion); resultado != tt.expected {
+				t.Errorf("ValidarOracion(%q) = %s (%s), expected %s", tt.oracion, resultado, explicacion, tt.expected)
+			}
+		})
+	}
+
+	tokens, err := robots.AnalizarLexico("robot beeped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resultado, explicacion := robots.ValidarTokens(tokens); resultado != "Valid" {
+		t.Errorf("robots.ValidarTokens() = %s (%s), expected Valid", resultado, explicacion)
+	}
+	if resultado, _ := estandar.ValidarTokens(tokens, ConPuntuacionEstricta()); resultado != "Invalid" {
+		t.Error("per-call options were not applied")
+	}
+}
+
+// TestValidadorActual tests that a snapshot validator keeps its dictionary across reloads
+func TestValidadorActual(t *testing.T) {
+	ruta, wordsData := diccionarioTemporal(t)
+	if err := RecargarDiccionario(); err != nil {
+		t.Fatal(err)
+	}
+	instantanea := ValidadorActual()
+
+	wordsData.Sujeto = append(wordsData.Sujeto, "grandpa")
+	escribirPalabras(t, ruta, wordsData)
+	if err := RecargarDiccionario(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, existe := instantanea.BuscarPalabra("grandpa"); existe {
+		t.Error("the snapshot validator sees a word added by a later reload")
+	}
+	if _, existe := ValidadorActual().BuscarPalabra("grandpa"); !existe {
+		t.Error("a new snapshot does not see the reloaded dictionary")
+	}
+}
@@ -0,0 +1,133 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"validar_oraciones/models"
+)
+
+// SubTipoIrregular marca en la metadata los pasados de verbos irregulares
+const SubTipoIrregular = "irregular"
+
+// FormasVerbo contiene las inflexiones de un verbo a partir de su forma base
+type FormasVerbo struct {
+	Base           string `json:"base"`
+	Pasado         string `json:"pasado"`
+	Participio     string `json:"participio"`
+	Gerundio       string `json:"gerundio"`
+	TerceraPersona string `json:"tercera_persona"`
+}
+
+// normalizada devuelve las inflexiones con las claves del diccionario
+func (f FormasVerbo) normalizada() FormasVerbo {
+	return FormasVerbo{
+		Base:           normalizarClave(f.Base),
+		Pasado:         normalizarClave(f.Pasado),
+		Participio:     normalizarClave(f.Participio),
+		Gerundio:       normalizarClave(f.Gerundio),
+		TerceraPersona: normalizarClave(f.TerceraPersona),
+	}
+}
+
+// formas devuelve cada inflexión junto con su texto, empezando por la base
+func (f FormasVerbo) formas() []struct {
+	Forma models.FormaVerbal
+	Texto string
+} {
+	return []struct {
+		Forma models.FormaVerbal
+		Texto string
+	}{
+		{models.FormaBase, f.Base},
+		{models.FormaPasado, f.Pasado},
+		{models.FormaParticipio, f.Participio},
+		{models.FormaGerundio, f.Gerundio},
+		{models.FormaTerceraPersona, f.TerceraPersona},
+	}
+}
+
+// esPresente indica si el token es un verbo en presente simple (base o tercera persona)
+func esPresente(token models.Token) bool {
+	return token.Metadata.Lema != "" &&
+		(token.Metadata.Forma == models.FormaBase || token.Metadata.Forma == models.FormaTerceraPersona)
+}
+
+// marcadoresPasado son las palabras que sitúan una expresión de tiempo en el pasado
+var marcadoresPasado = []string{"yesterday", "ago", "last", "past", "other day"}
+
+// buscarExpresionPasada devuelve la posición de la primera expresión de tiempo
+// pasada ("yesterday", "last night", "two days ago") o -1 si no hay ninguna
+func buscarExpresionPasada(tokens []models.Token) int {
+	for i, token := range tokens {
+		if token.Tipo != models.TipoTiempo && token.Tipo != models.TipoAdverbio {
+			continue
+		}
+		for _, marcador := range marcadoresPasado {
+			if strings.Contains(token.Texto, marcador) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// diagnosticoTiempoVerbal crea el diagnóstico para un verbo en presente dentro de
+// una oración en pasado, nombrando el pasado esperado
+func diagnosticoTiempoVerbal(d *Dictionary, tokens []models.Token, i, expresionPasada int) models.Diagnostic {
+	verbo := tokens[i].Texto
+	pasado, _ := d.PastOf(tokens[i].Metadata.Lema)
+
+	mensaje := fmt.Sprintf("The verb '%s' is not in the simple past. Use '%s'.", verbo, pasado)
+	if expresionPasada != -1 {
+		mensaje = fmt.Sprintf("The verb '%s' is in the present tense, but '%s' refers to the past. Use '%s'.",
+			verbo, tokens[expresionPasada].Texto, pasado)
+	}
+
+	diagnostico := nuevoDiagnostico(ReglaTiempoVerbal, mensaje, tokens, i, i+1)
+	diagnostico.Sugerencia = pasado
+	return diagnostico
+}
+
+// esTipoVerbal indica si el tipo del diccionario corresponde a un verbo
+func esTipoVerbal(tipo models.TipoPalabra) bool {
+	return tipo == models.TipoVerboSimple || tipo == models.TipoVerboAuxiliar
+}
+
+// Lemma devuelve la forma base de cualquier inflexión conocida ("went" -> "go")
+func (d *Dictionary) Lemma(palabra string) (string, bool) {
+	base, existe := d.lemas[strings.ToLower(palabra)]
+	return base, existe
+}
+
+// PastOf devuelve el pasado simple de un verbo en forma base ("go" -> "went")
+func (d *Dictionary) PastOf(base string) (string, bool) {
+	formas, existe := d.FormasDe(base)
+	return formas.Pasado, existe
+}
+
+// FormasDe devuelve todas las inflexiones de un verbo en forma base
+func (d *Dictionary) FormasDe(base string) (FormasVerbo, bool) {
+	formas, existe := d.formasVerbales[strings.ToLower(base)]
+	return formas, existe
+}
+
+// Lemma devuelve la forma base de una inflexión en el diccionario del paquete
+func Lemma(palabra string) (string, bool) {
+	return diccionarioActual().Lemma(palabra)
+}
+
+// PastOf devuelve el pasado simple de un verbo en el diccionario del paquete
+func PastOf(base string) (string, bool) {
+	return diccionarioActual().PastOf(base)
+}
+
+// FormasDe devuelve las inflexiones de un verbo en el diccionario del paquete
+func FormasDe(base string) (FormasVerbo, bool) {
+	return diccionarioActual().FormasDe(base)
+}
+
+// esPasadoIrregular indica si la palabra es el pasado de un verbo irregular
+func (d *Dictionary) esPasadoIrregular(pasado string) bool {
+	palabra, existe := d.buscar(pasado)
+	return existe && palabra.Metadata.SubTipo == SubTipoIrregular
+}
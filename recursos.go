@@ -0,0 +1,25 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+// recursos incluye en el binario el diccionario, las plantillas y los archivos
+// estáticos, así el servidor funciona desde cualquier directorio
+//
+//go:embed words.json templates static
+var recursos embed.FS
+
+// archivoDiccionario es el diccionario incluido en recursos
+const archivoDiccionario = "words.json"
+
+// directorio devuelve el directorio del disco si está configurado o, si no, el
+// subdirectorio incluido en el binario
+func directorio(configurado, incluido string) (fs.FS, error) {
+	if configurado != "" {
+		return os.DirFS(configurado), nil
+	}
+	return fs.Sub(recursos, incluido)
+}
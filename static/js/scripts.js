@@ -184,9 +184,123 @@ document.addEventListener('DOMContentLoaded', () => {
         }
     };
 
+    // Live validation: the text is sent to /api/live while the student types and
+    // the results arrive as Server-Sent Events
+    const liveValidator = {
+        DEBOUNCE_MS: 300,
+        sessionId: null,
+        version: 0,
+        timer: null,
+
+        init(form) {
+            this.container = document.getElementById('live-results');
+            if (!this.container || !window.EventSource) return;
+
+            this.form = form;
+            this.textarea = form.querySelector('textarea');
+            this.profile = form.querySelector('select[name="profile"]');
+            this.strict = form.querySelector('input[name="strict_punctuation"]');
+
+            const schedule = this.schedule.bind(this);
+            this.textarea.addEventListener('input', schedule);
+            this.profile.addEventListener('change', schedule);
+            this.strict.addEventListener('change', schedule);
+
+            // EventSource reconnects on its own; every connection is a new session
+            this.source = new EventSource('/api/live');
+            this.source.addEventListener('session', (e) => {
+                this.sessionId = JSON.parse(e.data).sesion;
+                if (this.textarea.value.trim()) this.send();
+            });
+            this.source.addEventListener('result', (e) => {
+                const result = JSON.parse(e.data);
+                if (result.version === this.version) this.render(result);
+            });
+        },
+
+        schedule() {
+            clearTimeout(this.timer);
+            this.timer = setTimeout(() => this.send(), this.DEBOUNCE_MS);
+        },
+
+        async send() {
+            if (!this.sessionId) return;
+
+            const text = this.textarea.value;
+            if (!text.trim()) {
+                this.version++;
+                this.container.replaceChildren();
+                return;
+            }
+
+            try {
+                await fetch('/api/live', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({
+                        sesion: this.sessionId,
+                        version: ++this.version,
+                        texto: text,
+                        profile: this.profile.value,
+                        strict_punctuation: this.strict.checked
+                    })
+                });
+            } catch (err) {
+                // The next keystroke or the next session retries
+            }
+        },
+
+        render(result) {
+            if (result.error) {
+                const message = document.createElement('p');
+                message.className = 'text-sm text-yellow-700 dark:text-yellow-300';
+                message.textContent = result.error;
+                this.container.replaceChildren(message);
+                return;
+            }
+            this.container.replaceChildren(...result.oraciones.map(sentence => this.renderSentence(sentence)));
+        },
+
+        renderSentence(sentence) {
+            const item = document.createElement('div');
+            item.className = `p-3 border rounded-md text-sm dark:border-gray-600 ${sentence.es_valida
+                ? 'bg-green-50 dark:bg-green-900/20'
+                : 'bg-red-50 dark:bg-red-900/20'}`;
+
+            const text = document.createElement('p');
+            text.className = 'text-gray-800 dark:text-gray-200';
+            for (const fragment of sentence.fragmentos || [{ texto: sentence.texto }]) {
+                if (!fragment.clase) {
+                    text.append(fragment.texto);
+                    continue;
+                }
+                const token = document.createElement('span');
+                token.className = `token tipo-${fragment.clase}${fragment.errores ? ' token-error' : ''}`;
+                token.title = [fragment.categoria, ...(fragment.errores || [])].join('\n');
+                token.textContent = fragment.texto;
+                text.appendChild(token);
+            }
+            item.appendChild(text);
+
+            if (!sentence.es_valida) {
+                const list = document.createElement('ul');
+                list.className = 'list-disc list-inside mt-1 text-gray-500 dark:text-gray-400';
+                const messages = (sentence.diagnosticos || []).map(d => d.mensaje);
+                for (const message of messages.length ? messages : [sentence.explicacion]) {
+                    const entry = document.createElement('li');
+                    entry.textContent = message;
+                    list.appendChild(entry);
+                }
+                item.appendChild(list);
+            }
+            return item;
+        }
+    };
+
     // Initialize components
     themeToggle.init();
     formValidator.init(document.getElementById('grammar-form'));
+    liveValidator.init(document.getElementById('grammar-form'));
 
     // Simplified event listeners for buttons
     const alertSystem = new AlertSystem();